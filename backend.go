@@ -0,0 +1,271 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const defaultBackend = "golangci-lint"
+
+// Backend is a linter the server runs. Its output is converted to the
+// golangci-lint result, so that every other feature works the same whatever
+// the backend.
+type Backend interface {
+	// defaultCommand returns the command run when none is configured.
+	defaultCommand() []string
+	// decode decodes the output of a run into result.
+	decode(r io.Reader, result *GolangCILintResult) error
+	// classify tells from the exit code of cfg's command whether it ran.
+	classify(cfg *config, code int) runKind
+}
+
+var backends = map[string]Backend{
+	defaultBackend: golangciLintBackend{},
+	"staticcheck":  staticcheckBackend{},
+	"revive":       reviveBackend{},
+	"sarif":        sarifBackend{},
+	vetBackend:     goVetBackend{},
+}
+
+// lookupBackend returns the backend called name, the default one if empty.
+func lookupBackend(name string) (Backend, error) {
+	if name == "" {
+		name = defaultBackend
+	}
+
+	b, ok := backends[name]
+	if !ok {
+		names := make([]string, 0, len(backends))
+		for n := range backends {
+			names = append(names, n)
+		}
+
+		sort.Strings(names)
+
+		return nil, fmt.Errorf("unknown backend %q, expected one of %v", name, names)
+	}
+
+	return b, nil
+}
+
+type golangciLintBackend struct{}
+
+func (golangciLintBackend) defaultCommand() []string {
+	return defaultCommand
+}
+
+func (golangciLintBackend) decode(r io.Reader, result *GolangCILintResult) error {
+	return decodeResult(r, result)
+}
+
+func (golangciLintBackend) classify(cfg *config, code int) runKind {
+	return classifyExitCode(code, cfg.issuesExitCode())
+}
+
+// staticcheckBackend runs staticcheck -f json, which prints one JSON object
+// per problem.
+type staticcheckBackend struct{}
+
+type staticcheckPosition struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+type staticcheckProblem struct {
+	Code     string              `json:"code"`
+	Severity string              `json:"severity"`
+	Location staticcheckPosition `json:"location"`
+	End      staticcheckPosition `json:"end"`
+	Message  string              `json:"message"`
+}
+
+func (staticcheckBackend) defaultCommand() []string {
+	return []string{"staticcheck", "-f", "json", "./..."}
+}
+
+func (staticcheckBackend) decode(r io.Reader, result *GolangCILintResult) error {
+	dec := json.NewDecoder(r)
+
+	for {
+		var p staticcheckProblem
+
+		err := dec.Decode(&p)
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if p.Severity == "ignored" {
+			continue
+		}
+
+		var issue Issue
+		issue.FromLinter = "staticcheck"
+		issue.Text = p.Code + ": " + p.Message
+		issue.Pos.Filename = p.Location.File
+		issue.Pos.Line = p.Location.Line
+		issue.Pos.Column = p.Location.Column
+		issue.LineRange.From = p.Location.Line
+		issue.LineRange.To = p.End.Line
+
+		result.addIssue(issue)
+	}
+}
+
+func (staticcheckBackend) classify(_ *config, code int) runKind {
+	// staticcheck exits with 1 when it found problems
+	if code == exitCodeSuccess || code == exitCodeIssuesFound {
+		return runIssues
+	}
+
+	return runFailed
+}
+
+// reviveBackend runs revive -formatter json, which prints a JSON array of
+// failures.
+type reviveBackend struct{}
+
+type revivePosition struct {
+	Filename string `json:"Filename"`
+	Offset   int    `json:"Offset"`
+	Line     int    `json:"Line"`
+	Column   int    `json:"Column"`
+}
+
+type reviveFailure struct {
+	Failure  string `json:"Failure"`
+	RuleName string `json:"RuleName"`
+	Position struct {
+		Start revivePosition `json:"Start"`
+		End   revivePosition `json:"End"`
+	} `json:"Position"`
+}
+
+func (reviveBackend) defaultCommand() []string {
+	return []string{"revive", "-formatter", "json", "./..."}
+}
+
+func (reviveBackend) decode(r io.Reader, result *GolangCILintResult) error {
+	var failures []reviveFailure
+	if err := json.NewDecoder(r).Decode(&failures); err != nil {
+		return err
+	}
+
+	for _, f := range failures {
+		var issue Issue
+		issue.FromLinter = "revive"
+		// golangci-lint reports revive failures the same way
+		issue.Text = f.RuleName + ": " + f.Failure
+		issue.Pos.Filename = f.Position.Start.Filename
+		issue.Pos.Offset = f.Position.Start.Offset
+		issue.Pos.Line = f.Position.Start.Line
+		issue.Pos.Column = f.Position.Start.Column
+		issue.LineRange.From = f.Position.Start.Line
+		issue.LineRange.To = f.Position.End.Line
+
+		result.addIssue(issue)
+	}
+
+	return nil
+}
+
+func (reviveBackend) classify(_ *config, code int) runKind {
+	// revive only exits with a failure code with -set_exit_status
+	if code == exitCodeSuccess || code == exitCodeIssuesFound {
+		return runIssues
+	}
+
+	return runFailed
+}
+
+// sarifBackend runs a configured command printing a SARIF log, such as gosec
+// or semgrep.
+type sarifBackend struct{}
+
+type sarifLog struct {
+	Runs []struct {
+		Tool struct {
+			Driver struct {
+				Name string `json:"name"`
+			} `json:"driver"`
+		} `json:"tool"`
+		Results []struct {
+			RuleID  string `json:"ruleId"`
+			Message struct {
+				Text string `json:"text"`
+			} `json:"message"`
+			Locations []struct {
+				PhysicalLocation struct {
+					ArtifactLocation struct {
+						URI string `json:"uri"`
+					} `json:"artifactLocation"`
+					Region struct {
+						StartLine   int `json:"startLine"`
+						StartColumn int `json:"startColumn"`
+						EndLine     int `json:"endLine"`
+					} `json:"region"`
+				} `json:"physicalLocation"`
+			} `json:"locations"`
+		} `json:"results"`
+	} `json:"runs"`
+}
+
+func (sarifBackend) defaultCommand() []string {
+	// there is no default SARIF producer
+	return nil
+}
+
+func (sarifBackend) decode(r io.Reader, result *GolangCILintResult) error {
+	var log sarifLog
+	if err := json.NewDecoder(r).Decode(&log); err != nil {
+		return err
+	}
+
+	for _, run := range log.Runs {
+		for _, res := range run.Results {
+			if len(res.Locations) == 0 {
+				continue
+			}
+
+			loc := res.Locations[0].PhysicalLocation
+
+			var issue Issue
+			issue.FromLinter = run.Tool.Driver.Name
+			issue.Text = res.Message.Text
+
+			if res.RuleID != "" {
+				issue.Text = res.RuleID + ": " + issue.Text
+			}
+
+			issue.Pos.Filename = loc.ArtifactLocation.URI
+			if strings.HasPrefix(issue.Pos.Filename, "file:") {
+				issue.Pos.Filename = uriToPath(issue.Pos.Filename)
+			}
+
+			issue.Pos.Line = loc.Region.StartLine
+			issue.Pos.Column = loc.Region.StartColumn
+			issue.LineRange.From = loc.Region.StartLine
+			issue.LineRange.To = loc.Region.EndLine
+
+			result.addIssue(issue)
+		}
+	}
+
+	return nil
+}
+
+func (sarifBackend) classify(_ *config, code int) runKind {
+	// most tools exit with 1 when they found issues
+	if code == exitCodeSuccess || code == exitCodeIssuesFound {
+		return runIssues
+	}
+
+	return runFailed
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// resultCache remembers the result of the last successful run per package
+// directory, keyed by a hash of the inputs of the run.
+type resultCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	Hash   string              `json:"hash"`
+	Result *GolangCILintResult `json:"result"`
+	// persisted is set for entries loaded from disk, which are used once
+	// to show diagnostics immediately and then refreshed by a new run.
+	persisted bool
+}
+
+func newResultCache() *resultCache {
+	return &resultCache{entries: make(map[string]cacheEntry)}
+}
+
+// get returns the cached result for dir if it was computed from the same
+// inputs. An entry loaded from disk is removed once returned, and persisted
+// is reported so that the caller schedules a fresh run.
+func (c *resultCache) get(dir, hash string) (result *GolangCILintResult, persisted, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[dir]
+	if !ok || e.Hash != hash {
+		return nil, false, false
+	}
+
+	if e.persisted {
+		delete(c.entries, dir)
+	}
+
+	return e.Result, e.persisted, true
+}
+
+func (c *resultCache) put(dir, hash string, result *GolangCILintResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[dir] = cacheEntry{Hash: hash, Result: result}
+}
+
+// cacheFile returns the file the results of the workspace rooted at rootDir
+// are persisted to.
+func cacheFile(rootDir string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(rootDir))
+
+	return filepath.Join(dir, "golangci-lint-langserver", "results", hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// load merges the entries persisted in name into the cache.
+func (c *resultCache) load(name string) error {
+	b, err := ioutil.ReadFile(name)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var entries map[string]cacheEntry
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for dir, e := range entries {
+		if _, ok := c.entries[dir]; !ok {
+			e.persisted = true
+			c.entries[dir] = e
+		}
+	}
+
+	return nil
+}
+
+// save writes the entries of the cache for the directories under root to
+// name, leaving out those of the other workspaces sharing the cache.
+func (c *resultCache) save(name, root string) error {
+	c.mu.Lock()
+
+	root = normalizePath(root)
+
+	entries := make(map[string]cacheEntry)
+	for dir, e := range c.entries {
+		if underPath(normalizePath(dir), root) {
+			entries[dir] = e
+		}
+	}
+
+	b, err := json.Marshal(entries)
+	c.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o700); err != nil {
+		return err
+	}
+
+	tmp := name + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, name)
+}
+
+// forget drops the cached result for dir.
+func (c *resultCache) forget(dir string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, dir)
+}
+
+// clearUnder drops the cached results of the directories under dirs, leaving
+// those of the other sessions sharing the cache.
+func (c *resultCache) clearUnder(dirs []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for dir := range c.entries {
+		n := normalizePath(dir)
+
+		for _, d := range dirs {
+			if underPath(n, normalizePath(d)) {
+				delete(c.entries, dir)
+
+				break
+			}
+		}
+	}
+}
+
+// packageHash hashes what a lint of the package in dir depends on: the lint
+// command and environment, the Go files of the package, the Go files of the
+// rest of the working directory, which the package may import and the run
+// lints too, as walked recently for trees, and the module, vendor and
+// golangci-lint configuration files.
+func (c *config) packageHash(dir string, trees *treeCache) (string, error) {
+	h := sha256.New()
+
+	_, _ = io.WriteString(h, strings.Join(c.lintCommand(), "\x00"))
+	_, _ = io.WriteString(h, "\x00"+strings.Join(c.lintEnv(), "\x00"))
+
+	for _, v := range c.variants {
+		_, _ = fmt.Fprintf(h, "\x00%+v", v)
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	files := make([]string, 0, len(entries))
+
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".go") {
+			files = append(files, filepath.Join(dir, e.Name()))
+		}
+	}
+
+	for _, name := range append([]string{"go.mod", "go.sum", "go.work", "go.work.sum", filepath.Join("vendor", "modules.txt")}, configNames...) {
+		files = append(files, filepath.Join(c.workingDir(), name))
+	}
+
+	for _, name := range files {
+		if err := hashFile(h, name); err != nil {
+			return "", err
+		}
+	}
+
+	tree, err := trees.sum(c.workingDir())
+	if err != nil {
+		return "", err
+	}
+
+	_, _ = io.WriteString(h, tree)
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// treeHashTTL is how long the walk of a working directory is reused. Saves
+// and file events forget it sooner; the delay catches the changes made
+// outside of the editor.
+const treeHashTTL = 2 * time.Second
+
+// treeCache remembers the recent walks of the working directories, so that
+// the lookups of the packages linted for a change share one.
+type treeCache struct {
+	mu   sync.Mutex
+	sums map[string]treeSum
+	// gen counts the forgets, so that the walks started before one are
+	// not remembered.
+	gen int
+}
+
+type treeSum struct {
+	sum string
+	at  time.Time
+}
+
+func newTreeCache() *treeCache {
+	return &treeCache{sums: make(map[string]treeSum)}
+}
+
+// sum returns the hash of the Go files under root, walking it unless it was
+// walked recently.
+func (t *treeCache) sum(root string) (string, error) {
+	t.mu.Lock()
+	s, ok := t.sums[root]
+	gen := t.gen
+	t.mu.Unlock()
+
+	if ok && time.Since(s.at) < treeHashTTL {
+		return s.sum, nil
+	}
+
+	at := time.Now()
+	h := sha256.New()
+
+	if err := hashTree(h, root); err != nil {
+		return "", err
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+
+	t.mu.Lock()
+	if t.gen == gen {
+		t.sums[root] = treeSum{sum: sum, at: at}
+	}
+	t.mu.Unlock()
+
+	return sum, nil
+}
+
+// forget forgets the walks, as files changed.
+func (t *treeCache) forget() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.sums = make(map[string]treeSum)
+	t.gen++
+}
+
+// hashTree hashes the name, size and modification time of the Go files of
+// the module under root. Hidden directories and node_modules hold no Go code
+// the build uses, vendor directories are hashed by their modules.txt and
+// nested modules are not linted with root.
+func hashTree(w io.Writer, root string) error {
+	return filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			// the file may be gone since the directory was read
+			if os.IsNotExist(err) {
+				return nil
+			}
+
+			return err
+		}
+
+		if info.IsDir() {
+			if p == root {
+				return nil
+			}
+
+			if name := info.Name(); strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor" {
+				return filepath.SkipDir
+			}
+
+			if _, err := os.Stat(filepath.Join(p, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !strings.HasSuffix(p, ".go") {
+			return nil
+		}
+
+		_, _ = fmt.Fprintf(w, "%s\x00%d\x00%d\x00", p, info.Size(), info.ModTime().UnixNano())
+
+		return nil
+	})
+}
+
+func hashFile(w io.Writer, name string) error {
+	f, err := os.Open(name)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, _ = io.WriteString(w, name+"\x00")
+	_, err = io.Copy(w, f)
+
+	return err
+}
+
+func (h *langHandler) loadCache(cfg *config) {
+	if !cfg.persistCache || cfg.rootDir == "" {
+		return
+	}
+
+	name, err := cacheFile(cfg.workspaceRoot())
+	if err == nil {
+		err = h.cache.load(name)
+	}
+
+	if err != nil {
+		h.logger.Errorf("golangci-lint-langserver: loading cached results: %s", err)
+	}
+}
+
+// saveCache persists the results of the workspace of cfg, which may be the
+// config of a file run in its module or folder.
+func (h *langHandler) saveCache(cfg *config) {
+	if !cfg.persistCache || cfg.rootDir == "" {
+		return
+	}
+
+	root := cfg.workspaceRoot()
+
+	name, err := cacheFile(root)
+	if err == nil {
+		err = h.cache.save(name, root)
+	}
+
+	if err != nil {
+		h.logger.Errorf("golangci-lint-langserver: saving cached results: %s", err)
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPackageHash(t *testing.T) {
+	root, err := ioutil.TempDir("", "golangci-lint-langserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	write := func(name, content string) {
+		t.Helper()
+
+		name = filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(name, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("go.mod", "module example.com/m\n")
+	write("a/a.go", "package a\n\nfunc F() {}\n")
+	write("b/b.go", "package b\n")
+	write(".git/x.go", "package x\n")
+
+	cfg := &config{rootDir: root, command: defaultCommand}
+	pkg := filepath.Join(root, "b")
+	trees := newTreeCache()
+
+	hash := func() string {
+		t.Helper()
+
+		// as a save does
+		trees.forget()
+
+		h, err := cfg.packageHash(pkg, trees)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return h
+	}
+
+	before := hash()
+	if again := hash(); again != before {
+		t.Fatalf("hash changed without changes: %s, %s", before, again)
+	}
+
+	write(".git/x.go", "package x\n\nvar X int\n")
+
+	if h := hash(); h != before {
+		t.Errorf("a change in a hidden directory changed the hash")
+	}
+
+	write("b/b.go", "package b\n\nvar B int\n")
+
+	changed := hash()
+	if changed == before {
+		t.Errorf("a change in the package kept the hash")
+	}
+
+	// the modification time may not change within the resolution of the
+	// file system
+	later := time.Now().Add(time.Second)
+	write("a/a.go", "package a\n\nfunc F(int) {}\n")
+
+	if err := os.Chtimes(filepath.Join(root, "a/a.go"), later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	if h := hash(); h == changed {
+		t.Errorf("a change in an imported package kept the hash")
+	}
+
+	changed = hash()
+
+	write("vendor/example.com/v/v.go", "package v\n")
+	write("nested/go.mod", "module example.com/nested\n")
+	write("nested/n.go", "package nested\n")
+
+	if h := hash(); h != changed {
+		t.Errorf("a change in a vendor directory or a nested module changed the hash")
+	}
+
+	write("vendor/modules.txt", "# example.com/v v1.0.0\n")
+
+	if h := hash(); h == changed {
+		t.Errorf("a change of the vendored modules kept the hash")
+	}
+}
+
+func TestTreeCache(t *testing.T) {
+	root, err := ioutil.TempDir("", "golangci-lint-langserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	trees := newTreeCache()
+
+	sum := func() string {
+		t.Helper()
+
+		s, err := trees.sum(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return s
+	}
+
+	before := sum()
+
+	if err := ioutil.WriteFile(filepath.Join(root, "a.go"), []byte("package a\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if again := sum(); again != before {
+		t.Errorf("the recent walk was not reused")
+	}
+
+	trees.forget()
+
+	if after := sum(); after == before {
+		t.Errorf("the walk was reused once forgotten")
+	}
+}
+
+func TestResultCacheSave(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golangci-lint-langserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := newResultCache()
+	c.put("/ws/a", "1", &GolangCILintResult{})
+	c.put("/ws/a/b", "2", &GolangCILintResult{})
+	c.put("/ws-other", "3", &GolangCILintResult{})
+	c.put("/other", "4", &GolangCILintResult{})
+
+	name := filepath.Join(dir, "cache.json")
+	if err := c.save(name, "/ws"); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := newResultCache()
+	if err := loaded.load(name); err != nil {
+		t.Fatal(err)
+	}
+
+	for dir, want := range map[string]bool{"/ws/a": true, "/ws/a/b": true, "/ws-other": false, "/other": false} {
+		if _, ok := loaded.entries[dir]; ok != want {
+			t.Errorf("%s saved = %v, want %v", dir, ok, want)
+		}
+	}
+}
+
+func TestPersistCacheForFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golangci-lint-langserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defer os.Setenv("XDG_CACHE_HOME", os.Getenv("XDG_CACHE_HOME"))
+	os.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+
+	root := filepath.Join(dir, "ws")
+	modDir := filepath.Join(root, "mod")
+	cfg := &config{rootDir: root, persistCache: true}
+
+	h := newLangHandler(newStdLogger(ioutil.Discard, 0, levelError, false), newSharedState())
+	defer h.close()
+
+	h.cache.put(modDir, "1", &GolangCILintResult{})
+	h.saveCache(cfg.withDir(modDir, dirFromModule))
+
+	loaded := newLangHandler(newStdLogger(ioutil.Discard, 0, levelError, false), newSharedState())
+	defer loaded.close()
+
+	loaded.loadCache(cfg)
+
+	if _, persisted, ok := loaded.cache.get(modDir, "1"); !ok || !persisted {
+		t.Errorf("result of %s not loaded from the session cache", modDir)
+	}
+}
+
+func TestResultCacheClearUnder(t *testing.T) {
+	c := newResultCache()
+	c.put("/ws/a", "1", &GolangCILintResult{})
+	c.put("/ws/a/b", "2", &GolangCILintResult{})
+	c.put("/ws-other", "3", &GolangCILintResult{})
+	c.put("/lib", "4", &GolangCILintResult{})
+
+	c.clearUnder([]string{"/ws", "/lib"})
+
+	for dir, want := range map[string]bool{"/ws/a": false, "/ws/a/b": false, "/ws-other": true, "/lib": false} {
+		if _, ok := c.entries[dir]; ok != want {
+			t.Errorf("%s kept = %v, want %v", dir, ok, want)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
+
+	"github.com/sourcegraph/jsonrpc2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	codeActionKindQuickFix = "quickfix"
+
+	excludeTitle = "Exclude this issue in project config"
+
+	// the code actions whose edit codeAction/resolve computes
+	resolveExclude = "exclude"
+	resolveDisable = "disable"
+)
+
+// codeActionData identifies a code action left for codeAction/resolve to
+// complete.
+type codeActionData struct {
+	Action     string          `json:"action"`
+	URI        DocumentURI     `json:"uri"`
+	Diagnostic *diagnosticData `json:"diagnostic,omitempty"`
+	Linter     string          `json:"linter,omitempty"`
+}
+
+// resolvesEdits reports whether the client can resolve the edits of code
+// actions.
+func resolvesEdits(support *CodeActionResolveSupport) bool {
+	if support == nil {
+		return false
+	}
+
+	for _, p := range support.Properties {
+		if p == "edit" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *langHandler) handleTextDocumentCodeAction(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (result interface{}, err error) {
+	var params CodeActionParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, err
+	}
+
+	uri := params.TextDocument.URI
+	fixed := fixedDocuments(uri, params.Context.Diagnostics)
+	versions := h.documentVersions(fixed)
+	filename := uriToPath(string(uri))
+	cfg := h.config().forFile(filename)
+	actions := make([]CodeAction, 0)
+	linters := make(map[string]bool)
+
+	for i := range params.Context.Diagnostics {
+		d := &params.Context.Diagnostics[i]
+
+		data, ok := decodeData(d)
+		if !ok {
+			continue
+		}
+
+		if action, ok := h.removeNolintAction(uri, d, data); ok {
+			actions = append(actions, *action)
+		}
+
+		if action, ok := h.fixAction(cfg, uri, d, data); ok {
+			actions = append(actions, *action)
+		}
+
+		actions = append(actions, muteAction(uri, d, data))
+
+		if cfg.resolveEdits {
+			actions = append(actions, h.unresolvedActions(cfg, uri, d, data, linters)...)
+
+			continue
+		}
+
+		edit, err := h.excludeEdit(cfg, filename, data)
+		if err != nil {
+			h.logger.Debugf("golangci-lint-langserver: exclude action: %s", err)
+
+			continue
+		}
+
+		actions = append(actions, CodeAction{
+			Title:       excludeTitle,
+			Kind:        codeActionKindQuickFix,
+			Diagnostics: []Diagnostic{*d},
+			Edit:        edit,
+		})
+
+		if linters[data.Linter] {
+			continue
+		}
+
+		linters[data.Linter] = true
+
+		if name, edit, err := h.disableEdit(cfg, data.Linter); err == nil {
+			actions = append(actions, CodeAction{
+				Title: disableTitle(data.Linter, name),
+				Kind:  codeActionKindQuickFix,
+				Edit:  edit,
+			})
+		}
+	}
+
+	if n := h.mutes().count(uri); n > 0 {
+		actions = append(actions, unmuteAction(uri, n))
+	}
+
+	// the edits would apply to outdated buffers
+	if !reflect.DeepEqual(h.documentVersions(fixed), versions) {
+		return nil, &jsonrpc2.Error{Code: CodeContentModified, Message: "content modified"}
+	}
+
+	return actions, nil
+}
+
+// unresolvedActions returns the actions rewriting the project's golangci-lint
+// configuration for d without their edits, which parse and rewrite the whole
+// file. Only its name is looked up here.
+func (h *langHandler) unresolvedActions(cfg *config, uri DocumentURI, d *Diagnostic, data *diagnosticData, linters map[string]bool) []CodeAction {
+	name, err := h.projectConfigName(cfg)
+	if err != nil {
+		h.logger.Debugf("golangci-lint-langserver: exclude action: %s", err)
+
+		return nil
+	}
+
+	actions := []CodeAction{{
+		Title:       excludeTitle,
+		Kind:        codeActionKindQuickFix,
+		Diagnostics: []Diagnostic{*d},
+		Data:        &codeActionData{Action: resolveExclude, URI: uri, Diagnostic: data},
+	}}
+
+	if !linters[data.Linter] {
+		linters[data.Linter] = true
+
+		actions = append(actions, CodeAction{
+			Title: disableTitle(data.Linter, name),
+			Kind:  codeActionKindQuickFix,
+			Data:  &codeActionData{Action: resolveDisable, URI: uri, Linter: data.Linter},
+		})
+	}
+
+	return actions
+}
+
+func (h *langHandler) handleCodeActionResolve(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (result interface{}, err error) {
+	var action CodeAction
+	if err := json.Unmarshal(*req.Params, &action); err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(action.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var data codeActionData
+	if err := json.Unmarshal(b, &data); err != nil || data.Action == "" {
+		// nothing left to compute
+		return action, nil
+	}
+
+	filename := uriToPath(string(data.URI))
+	cfg := h.config().forFile(filename)
+
+	var edit *WorkspaceEdit
+
+	switch {
+	case data.Action == resolveExclude && data.Diagnostic != nil:
+		edit, err = h.excludeEdit(cfg, filename, data.Diagnostic)
+	case data.Action == resolveDisable && data.Linter != "":
+		_, edit, err = h.disableEdit(cfg, data.Linter)
+	default:
+		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidParams, Message: fmt.Sprintf("unknown code action: %s", data.Action)}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	action.Edit = edit
+
+	return action, nil
+}
+
+// disableTitle is the title of the action disabling linter in the
+// configuration file name.
+func disableTitle(linter, name string) string {
+	return fmt.Sprintf("Disable %s in %s", linter, filepath.Base(name))
+}
+
+// excludeEdit returns the edit adding a rule excluding the issue of data to
+// the project's golangci-lint configuration, so that CI ignores it too.
+func (h *langHandler) excludeEdit(cfg *config, filename string, data *diagnosticData) (*WorkspaceEdit, error) {
+	p, err := h.projectConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules *yaml.Node
+	if p.v2 {
+		rules = yamlChild(yamlChild(yamlChild(p.root(), "linters", yaml.MappingNode), "exclusions", yaml.MappingNode), "rules", yaml.SequenceNode)
+	} else {
+		rules = yamlChild(yamlChild(p.root(), "issues", yaml.MappingNode), "exclude-rules", yaml.SequenceNode)
+	}
+
+	rules.Content = append(rules.Content, &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+		yamlString("path"), yamlString("^" + regexp.QuoteMeta(p.relPath(filename)) + "$"),
+		yamlString("linters"), {Kind: yaml.SequenceNode, Content: []*yaml.Node{yamlString(data.Linter)}},
+		yamlString("text"), yamlString(regexp.QuoteMeta(data.Text)),
+	}})
+
+	return p.edit(cfg.changeAnnotations)
+}
+
+// disableEdit returns the name of the project's golangci-lint configuration
+// and the edit disabling linter in it.
+func (h *langHandler) disableEdit(cfg *config, linter string) (string, *WorkspaceEdit, error) {
+	p, err := h.projectConfig(cfg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	linters := yamlChild(p.root(), "linters", yaml.MappingNode)
+
+	// a linter both enabled and disabled is a configuration error
+	if enable := yamlValue(linters, "enable"); enable != nil && enable.Kind == yaml.SequenceNode {
+		content := enable.Content[:0]
+
+		for _, n := range enable.Content {
+			if n.Value != linter {
+				content = append(content, n)
+			}
+		}
+
+		enable.Content = content
+	}
+
+	disable := yamlChild(linters, "disable", yaml.SequenceNode)
+
+	for _, n := range disable.Content {
+		if n.Value == linter {
+			return "", nil, fmt.Errorf("%s is already disabled", linter)
+		}
+	}
+
+	disable.Content = append(disable.Content, yamlString(linter))
+
+	edit, err := p.edit(cfg.changeAnnotations)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return p.name, edit, nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// commandFocusProblems is the client command opening the Problems panel of
+// VS Code.
+const commandFocusProblems = "workbench.actions.view.problems"
+
+// maxLensLinters is the number of linters named by the issue count lens.
+const maxLensLinters = 3
+
+func (h *langHandler) handleTextDocumentCodeLens(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (result interface{}, err error) {
+	var params CodeLensParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, err
+	}
+
+	lenses := make([]CodeLens, 0, 1)
+
+	if !h.config().codeLens {
+		return lenses, nil
+	}
+
+	h.pubMu.Lock()
+	counts, ok := h.issueCounts[params.TextDocument.URI]
+	h.pubMu.Unlock()
+
+	if !ok {
+		return lenses, nil
+	}
+
+	return append(lenses, CodeLens{
+		Command: &Command{Title: lensTitle(counts), Command: commandFocusProblems},
+	}), nil
+}
+
+// lensTitle summarizes the number of issues per linter, naming the linters
+// reporting the most.
+func lensTitle(counts map[string]int) string {
+	linters := make([]string, 0, len(counts))
+	total := 0
+
+	for linter, n := range counts {
+		linters = append(linters, linter)
+		total += n
+	}
+
+	if total == 0 {
+		return "golangci-lint: no issues"
+	}
+
+	sort.Slice(linters, func(i, j int) bool {
+		if counts[linters[i]] != counts[linters[j]] {
+			return counts[linters[i]] > counts[linters[j]]
+		}
+
+		return linters[i] < linters[j]
+	})
+
+	parts := make([]string, 0, maxLensLinters+1)
+
+	for i, linter := range linters {
+		if i == maxLensLinters {
+			parts = append(parts, "…")
+
+			break
+		}
+
+		parts = append(parts, fmt.Sprintf("%s %d", linter, counts[linter]))
+	}
+
+	noun := "issues"
+	if total == 1 {
+		noun = "issue"
+	}
+
+	return fmt.Sprintf("golangci-lint: %d %s (%s)", total, noun, strings.Join(parts, ", "))
+}
+
+// countIssues returns the number of diagnostics per source.
+func countIssues(diagnostics []Diagnostic) map[string]int {
+	counts := make(map[string]int)
+
+	for _, d := range diagnostics {
+		if d.Source != nil {
+			counts[*d.Source]++
+		}
+	}
+
+	return counts
+}
+
+// refreshCodeLenses asks the client to request the code lenses again once the
+// diagnostics of an open document changed.
+func (h *langHandler) refreshCodeLenses(cfg *config, uri DocumentURI) {
+	if !cfg.codeLens || !cfg.codeLensRefresh {
+		return
+	}
+
+	if _, ok := h.documentText(uri); !ok {
+		return
+	}
+
+	go func() {
+		if err := cfg.conn.Call(context.Background(), "workspace/codeLens/refresh", nil, nil); err != nil {
+			h.logger.Debugf("golangci-lint-langserver: refreshing code lenses: %s", err)
+		}
+	}()
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+func (h *langHandler) handleWorkspaceExecuteCommand(ctx context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (result interface{}, err error) {
+	var params ExecuteCommandParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, err
+	}
+
+	switch params.Command {
+	case commandLint:
+		return h.handleLintCommand(&params)
+	case commandShowOutput:
+		return h.handleShowOutput(ctx)
+	case commandMute:
+		return h.handleMuteCommand(&params, true)
+	case commandUnmute:
+		return h.handleMuteCommand(&params, false)
+	}
+
+	return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidParams, Message: fmt.Sprintf("command not supported: %s", params.Command)}
+}
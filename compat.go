@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	goModRequirePattern = regexp.MustCompile(`(?m)^\s*(?:require\s+)?github\.com/golangci/golangci-lint(?:/v\d+)?\s+v(\S+)`)
+	configNames         = []string{".golangci.yml", ".golangci.yaml", ".golangci.toml", ".golangci.json"}
+)
+
+// requirements are the golangci-lint related versions declared by a project.
+type requirements struct {
+	// Version is the golangci-lint version required in go.mod.
+	Version string
+	// ConfigVersion is the major version of the .golangci.yml format.
+	ConfigVersion string
+	// GoVersion is the Go version set by run.go in .golangci.yml.
+	GoVersion string
+}
+
+func readRequirements(dir string) requirements {
+	var req requirements
+
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+		if m := goModRequirePattern.FindSubmatch(b); m != nil {
+			req.Version = string(m[1])
+		}
+	}
+
+	for _, name := range configNames[:2] {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+
+		var config struct {
+			Version string `yaml:"version"`
+			Run     struct {
+				Go string `yaml:"go"`
+			} `yaml:"run"`
+		}
+
+		if err := yaml.Unmarshal(b, &config); err == nil {
+			req.ConfigVersion = config.Version
+			req.GoVersion = config.Run.Go
+		}
+
+		break
+	}
+
+	return req
+}
+
+// checkCompatibility compares the detected golangci-lint version against the
+// requirements of the project and returns warnings describing mismatches.
+func checkCompatibility(info versionInfo, req requirements) []string {
+	var warnings []string
+
+	if req.Version != "" && compareVersions(info.Version, req.Version) != 0 {
+		warnings = append(warnings, fmt.Sprintf("golangci-lint %s is installed but go.mod requires %s", info.Version, req.Version))
+	}
+
+	if req.ConfigVersion != "" && !strings.HasPrefix(info.Version, req.ConfigVersion+".") {
+		warnings = append(warnings, fmt.Sprintf("golangci-lint %s does not support configuration version %s", info.Version, req.ConfigVersion))
+	}
+
+	if req.GoVersion != "" && info.GoVersion != "" && compareVersions(info.GoVersion, req.GoVersion) < 0 {
+		warnings = append(warnings, fmt.Sprintf("golangci-lint %s is built with go%s but run.go is %s", info.Version, info.GoVersion, req.GoVersion))
+	}
+
+	return warnings
+}
+
+// binaryCommand returns the part of the lint command that invokes the
+// golangci-lint binary, that is without the subcommand and its arguments.
+func (c *config) binaryCommand() []string {
+	for i, arg := range c.command {
+		if arg == "run" {
+			return c.command[:i]
+		}
+	}
+
+	if len(c.command) == 0 {
+		return c.command
+	}
+
+	return c.command[:1]
+}
+
+// outputCommand returns the command of c with the --out-format json of
+// golangci-lint v1, as in the default command, replaced by the flags of
+// golangci-lint v2, which removed it.
+func (c *config) outputCommand() []string {
+	if c.version == "" || compareVersions(c.version, "2.0.0") < 0 {
+		return c.command
+	}
+
+	for i, arg := range c.command {
+		var n int
+
+		switch {
+		case arg == "--out-format" && i+1 < len(c.command) && c.command[i+1] == "json":
+			n = 2
+		case arg == "--out-format=json":
+			n = 1
+		default:
+			continue
+		}
+
+		command := append(append([]string{}, c.command[:i]...), "--output.json.path", "stdout")
+
+		return append(command, c.command[i+n:]...)
+	}
+
+	return c.command
+}
+
+func (h *langHandler) checkVersion() {
+	cfg := h.config()
+
+	if cfg.fallback != "" {
+		h.showMessage(MTWarning, fmt.Sprintf("golangci-lint-langserver: %s not found, falling back to go vet with basic diagnostics only", cfg.fallback))
+
+		return
+	}
+
+	// golangci-lint does not run in offline mode or when replaying fixtures
+	if cfg.backendName != defaultBackend || cfg.report != nil || cfg.fixtures != nil {
+		return
+	}
+
+	info, err := cfg.golangciLintVersion()
+	if err != nil {
+		h.logger.Errorf("golangci-lint-langserver: detecting golangci-lint version: %s", err)
+
+		return
+	}
+
+	h.logger.Printf("golangci-lint-langserver: golangci-lint version %s", info.Version)
+	h.updateConfig(func(c *config) {
+		c.version = info.Version
+	})
+
+	for _, warning := range checkCompatibility(info, readRequirements(cfg.rootDir)) {
+		h.showMessage(MTWarning, warning+"; diagnostics may differ from CI")
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOutputCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		command []string
+		want    []string
+	}{
+		{
+			name:    "unknown version",
+			command: defaultCommand,
+			want:    defaultCommand,
+		},
+		{
+			name:    "v1",
+			version: "1.64.8",
+			command: defaultCommand,
+			want:    defaultCommand,
+		},
+		{
+			name:    "v2",
+			version: "2.1.6",
+			command: defaultCommand,
+			want:    []string{"golangci-lint", "run", "--output.json.path", "stdout"},
+		},
+		{
+			name:    "v2 with an equals sign",
+			version: "2.1.6",
+			command: []string{"golangci-lint", "run", "--out-format=json", "--fix"},
+			want:    []string{"golangci-lint", "run", "--output.json.path", "stdout", "--fix"},
+		},
+		{
+			name:    "v2 flags",
+			version: "2.1.6",
+			command: []string{"golangci-lint", "run", "--output.json.path", "stdout"},
+			want:    []string{"golangci-lint", "run", "--output.json.path", "stdout"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &config{version: tt.version, command: tt.command}
+			if got := c.outputCommand(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("outputCommand() = %q, want %q", got, tt.want)
+			}
+
+			if !reflect.DeepEqual(defaultCommand, []string{"golangci-lint", "run", "--out-format", "json"}) {
+				t.Fatalf("defaultCommand modified: %q", defaultCommand)
+			}
+		})
+	}
+}
@@ -0,0 +1,468 @@
+package main
+
+import (
+	"os"
+	"text/template"
+	"time"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// config is the state established by initialize. A published config is never
+// mutated; updates replace it as a whole, so that it can be read concurrently
+// without holding the lock.
+type config struct {
+	conn             *jsonrpc2.Conn
+	params           *InitializeParams
+	rootURI          string
+	rootDir          string
+	workspaceFolders []WorkspaceFolder
+	folders          map[string]*FolderOptions
+	backendName      string
+	backend          Backend
+	// fallback is the binary that was not found when falling back to go vet.
+	fallback         string
+	command          []string
+	executor         executor
+	sandbox          *SandboxOptions
+	warmCache        bool
+	persistCache     bool
+	install          *InstallOptions
+	severities       map[string]DiagnosticSeverity
+	minSeverity      DiagnosticSeverity
+	testSeverity     DiagnosticSeverity
+	rules            []rule
+	messageTemplate  *template.Template
+	maxPerFile       int
+	maxTotal         int
+	maxTrackedFiles  int
+	excludePaths     []string
+	ignoreFiles      []string
+	includeGenerated bool
+	enabledLinters   []string
+	disabledLinters  []string
+	unusedNolint     bool
+	gopls            *GoplsOptions
+	duplicates       *DuplicatesOptions
+	telemetry        *TelemetryOptions
+	workDoneProgress bool
+	showDocument     bool
+	concurrency      int
+	gogc             string
+	cacheDir         string
+	env              map[string]string
+	packagesDriver   string
+	gitMode          string
+	strategy         string
+	changeDelay      time.Duration
+	// idleDelay is how long changes pause before the onChange trigger fires.
+	idleDelay time.Duration
+	triggers  runTriggers
+	// includeVendor, includeTestdata and includeGitIgnored lint the files
+	// skipped by default.
+	includeVendor     bool
+	includeTestdata   bool
+	includeGitIgnored bool
+	// lintWorkspaceOnStart publishes the diagnostics of the whole workspace
+	// once initialized.
+	lintWorkspaceOnStart bool
+	// lintDependents lints the open files importing a saved package.
+	lintDependents bool
+	// lintTests, when set, passes --tests to golangci-lint run.
+	lintTests *bool
+	// skipUnchanged does not publish the diagnostics a file already has
+	// again.
+	skipUnchanged bool
+	// roots are the normalized directories whose files get diagnostics.
+	roots []string
+	// changeAnnotations asks the client to confirm the edits rewriting
+	// several lines.
+	changeAnnotations bool
+	// documentChanges sends the edits of fixes with document versions.
+	documentChanges bool
+	// watchDeletes registers a watcher for the Go files deleted outside of
+	// the editor.
+	watchDeletes bool
+	// resolveEdits defers the edits of the code actions rewriting the
+	// project configuration to codeAction/resolve.
+	resolveEdits bool
+	// codeLens shows the number of issues at the top of files, refreshed
+	// with codeLensRefresh.
+	codeLens        bool
+	codeLensRefresh bool
+	// diagnosticRefresh asks pull-diagnostics clients to query the
+	// diagnostics again once the configuration changed. diagnosticPull
+	// tells the client can pull them.
+	diagnosticRefresh bool
+	diagnosticPull    bool
+	// allowParallelRunners lets golangci-lint run alongside other instances
+	// instead of waiting for their lock.
+	allowParallelRunners bool
+	// stats records the timings of the runs for golangci-lint/stats.
+	stats bool
+	// explicitDir is the workingDir setting, dirSource where rootDir, the
+	// working directory, comes from.
+	explicitDir string
+	dirSource   string
+	// sessionRoot is the root of the session a config for a file was
+	// derived from, when it runs in another directory.
+	sessionRoot string
+	// singleFile is the file linted alone, being outside of any module and
+	// GOPATH.
+	singleFile string
+	// report, when set, replaces the runs with the issues of a report
+	// file.
+	report *offlineReport
+	// fixtures, when set, replace the golangci-lint runs with recorded
+	// ones.
+	fixtures *fixtureSet
+	// variants are the build configurations every run is made in, variant
+	// the one of a run.
+	variants []VariantOptions
+	variant  *VariantOptions
+	// linterToggles are the linters enabled (true) or disabled (false) for
+	// the session with golangci-lint/setLinters.
+	linterToggles map[string]bool
+	// discoverRoots makes the modules of the linted files workspace folders,
+	// the client having opened no workspace.
+	discoverRoots bool
+	// version is the detected golangci-lint version, if known.
+	version string
+	// untrusted is the workspace configuration file whose command options
+	// were ignored, if any.
+	untrusted *untrustedConfig
+}
+
+// newConfig builds the config from the initialize params and the options
+// loaded from the configuration files.
+func (h *langHandler) newConfig(conn *jsonrpc2.Conn, params *InitializeParams) *config {
+	rootURI := params.rootURI()
+	rootDir := uriToPath(rootURI)
+	opts, untrusted := h.loadOptions(rootDir, params.InitializationOptions)
+
+	if opts.LogLevel != "" {
+		if level, err := parseLogLevel(opts.LogLevel); err == nil {
+			h.logger.SetLevel(level)
+		} else {
+			h.logger.Errorf("golangci-lint-langserver: %s", err)
+		}
+	}
+
+	if opts.Debug {
+		h.logger.SetLevel(levelTrace)
+	}
+
+	cfg := &config{
+		conn:             conn,
+		params:           params,
+		discoverRoots:    rootURI == "",
+		untrusted:        untrusted,
+		rootURI:          rootURI,
+		rootDir:          rootDir,
+		workspaceFolders: params.WorkspaceFolders,
+		folders:          folderDirs(rootDir, params.WorkspaceFolders, opts.Folders),
+		backendName:      defaultBackend,
+		backend:          backends[defaultBackend],
+		command:          opts.Command,
+		warmCache:        opts.WarmCache,
+		persistCache:     opts.PersistCache,
+		install:          opts.Install,
+		fixtures:         h.fixtures,
+		severities:       parseSeverities(opts.Severity),
+		rules:            h.compileRules(opts.Rules),
+		messageTemplate:  h.parseMessageTemplate(opts.MessageTemplate),
+		maxPerFile:       opts.MaxDiagnosticsPerFile,
+		maxTotal:         opts.MaxDiagnostics,
+		maxTrackedFiles:  defaultMaxTrackedFiles,
+		excludePaths:     opts.ExcludePaths,
+		ignoreFiles:      opts.IgnoreFiles,
+		includeGenerated: opts.IncludeGenerated,
+		enabledLinters:   opts.EnabledLinters,
+		disabledLinters:  opts.DisabledLinters,
+		unusedNolint:     opts.UnusedNolint,
+		gopls:            opts.Gopls,
+		duplicates:       opts.Duplicates,
+		telemetry:        opts.Telemetry,
+		workDoneProgress: params.Capabilities.Window.WorkDoneProgress,
+		showDocument:     params.Capabilities.Window.ShowDocument.Support,
+		concurrency:      opts.Concurrency,
+		gogc:             opts.GOGC,
+		cacheDir:         opts.CacheDir,
+		env:              opts.Env,
+		packagesDriver:   opts.PackagesDriver,
+		strategy:         strategySave,
+		changeDelay:      defaultChangeDelay,
+
+		includeVendor:     opts.IncludeVendor,
+		includeTestdata:   opts.IncludeTestdata,
+		includeGitIgnored: opts.IncludeGitIgnored,
+
+		lintWorkspaceOnStart: opts.LintWorkspaceOnStart,
+		lintDependents:       opts.LintDependents,
+		lintTests:            opts.LintTests,
+		skipUnchanged:        opts.SkipUnchanged,
+		variants:             opts.Variants,
+
+		changeAnnotations: params.Capabilities.Workspace.WorkspaceEdit.DocumentChanges &&
+			params.Capabilities.Workspace.WorkspaceEdit.ChangeAnnotationSupport != nil,
+		documentChanges: params.Capabilities.Workspace.WorkspaceEdit.DocumentChanges,
+		watchDeletes:    params.Capabilities.Workspace.DidChangeWatchedFiles.DynamicRegistration,
+		resolveEdits:    resolvesEdits(params.Capabilities.TextDocument.CodeAction.ResolveSupport),
+
+		codeLens:        opts.CodeLens,
+		codeLensRefresh: params.Capabilities.Workspace.CodeLens.RefreshSupport,
+
+		diagnosticRefresh: params.Capabilities.Workspace.Diagnostics.RefreshSupport,
+		diagnosticPull:    params.Capabilities.TextDocument.Diagnostic != nil,
+
+		allowParallelRunners: opts.AllowParallelRunners,
+		stats:                opts.Stats,
+	}
+
+	if b, err := lookupBackend(opts.Backend); err == nil {
+		cfg.backend = b
+		if opts.Backend != "" {
+			cfg.backendName = opts.Backend
+		}
+	} else {
+		h.logger.Errorf("golangci-lint-langserver: %s", err)
+	}
+
+	if opts.MinSeverity != "" {
+		if s, ok := parseSeverity(opts.MinSeverity); ok {
+			cfg.minSeverity = s
+		} else {
+			h.logger.Errorf("golangci-lint-langserver: unknown minSeverity %q", opts.MinSeverity)
+		}
+	}
+
+	if opts.TestSeverity != "" {
+		if s, ok := parseSeverity(opts.TestSeverity); ok {
+			cfg.testSeverity = s
+		} else {
+			h.logger.Errorf("golangci-lint-langserver: unknown testSeverity %q", opts.TestSeverity)
+		}
+	}
+
+	if opts.WorkingDir != "" {
+		cfg.explicitDir = resolveDir(rootDir, opts.WorkingDir)
+	}
+
+	if opts.ReportFile != "" {
+		cfg.report = newOfflineReport(rootDir, opts.ReportFile)
+	}
+
+	if cfg.install != nil && cfg.fixtures != nil {
+		// the fixtures need no golangci-lint binary
+		cfg.install = nil
+	}
+
+	if cfg.install != nil && cfg.backendName != defaultBackend {
+		h.logger.Errorf("golangci-lint-langserver: install is only supported for golangci-lint")
+		cfg.install = nil
+	}
+
+	switch opts.GitMode {
+	case "", gitDirty, gitChangedLines:
+		cfg.gitMode = opts.GitMode
+	default:
+		h.logger.Errorf("golangci-lint-langserver: unknown gitMode %q", opts.GitMode)
+	}
+
+	switch opts.Strategy {
+	case "", strategySave:
+	case strategyTwoTier:
+		cfg.strategy = opts.Strategy
+	default:
+		h.logger.Errorf("golangci-lint-langserver: unknown strategy %q", opts.Strategy)
+	}
+
+	if opts.Gopls != nil {
+		switch opts.Gopls.Dedup {
+		case "":
+			opts.Gopls.Dedup = goplsDrop
+		case goplsDrop, goplsTag:
+		default:
+			h.logger.Errorf("golangci-lint-langserver: unknown gopls dedup mode %q", opts.Gopls.Dedup)
+			cfg.gopls = nil
+		}
+	}
+
+	if opts.MaxTrackedFiles > 0 {
+		cfg.maxTrackedFiles = opts.MaxTrackedFiles
+	}
+
+	cfg.idleDelay = defaultIdleDelay
+	if opts.IdleDelay > 0 {
+		cfg.idleDelay = time.Duration(opts.IdleDelay) * time.Millisecond
+	}
+
+	// clients not sending didSave lint on change unless told otherwise
+	idle := opts.IdleDelay > 0 || !params.Capabilities.TextDocument.Synchronization.DidSave
+	if triggers, err := parseRunTriggers(opts.RunTriggers, idle); err == nil {
+		cfg.triggers = triggers
+	} else {
+		h.logger.Errorf("golangci-lint-langserver: %s", err)
+		cfg.triggers, _ = parseRunTriggers(nil, idle)
+	}
+
+	if opts.ChangeDelay > 0 {
+		cfg.changeDelay = time.Duration(opts.ChangeDelay) * time.Millisecond
+	}
+
+	switch {
+	case opts.Container != nil:
+		cfg.executor = newContainerExecutor(*opts.Container, cfg.rootDir)
+	case opts.SSH != nil:
+		cfg.executor = newSSHExecutor(*opts.SSH, cfg.rootDir)
+	}
+
+	if cfg.executor != nil && cfg.explicitDir != "" {
+		cfg.executor = cfg.executor.forDir(cfg.explicitDir)
+	}
+
+	if opts.Sandbox != nil {
+		cfg.sandbox = opts.Sandbox
+
+		// the runs fail rather than go unsandboxed
+		if _, err := cfg.sandbox.wrap(nil, nil, ""); err != nil {
+			h.logger.Errorf("golangci-lint-langserver: sandbox: %s", err)
+		}
+
+		if cfg.executor != nil {
+			h.logger.Errorf("golangci-lint-langserver: sandbox is ignored when running golangci-lint in a container or over SSH")
+		}
+	}
+
+	if rootDir != "" {
+		addWorkModules(rootDir, cfg.folders)
+		cfg.roots = workspaceRoots(rootDir, cfg.folders, opts.IncludeReplaced)
+	}
+
+	if err := cfg.useModuleTool(opts.CommandResolution); err != nil {
+		h.logger.Errorf("golangci-lint-langserver: commandResolution: %s", err)
+	}
+	cfg.fallBackToVet()
+
+	return cfg
+}
+
+func (h *langHandler) config() *config {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.cfg
+}
+
+func (h *langHandler) setConfig(c *config) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.cfg = c
+}
+
+// updateConfig replaces the config with a copy modified by update.
+func (h *langHandler) updateConfig(update func(c *config)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	c := *h.cfg
+	update(&c)
+	h.cfg = &c
+}
+
+func (c *config) lintCommand(extra ...string) []string {
+	return c.executorCommand(c.runCommand(extra...))
+}
+
+// niceLintCommand is lintCommand with a low priority. With an executor, the
+// remote command gets it rather than the local client running it.
+func (c *config) niceLintCommand(extra ...string) []string {
+	if c.executor != nil {
+		return c.executorCommand(append([]string{"nice", "-n", "19"}, c.runCommand(extra...)...))
+	}
+
+	return niceCommand(c.runCommand(extra...))
+}
+
+// runCommand returns the lint command with its flags, as run by the executor
+// if any.
+func (c *config) runCommand(extra ...string) []string {
+	command := c.outputCommand()
+
+	// only golangci-lint run understands the flags
+	flags := append(append(append(append(c.linterFlags(), c.testFlags()...), c.variantFlags()...), c.resourceFlags()...), extra...)
+	if c.singleFile != "" {
+		flags = append(flags, c.singleFile)
+	}
+
+	if len(flags) > 0 && len(c.binaryCommand()) < len(command) && command[len(c.binaryCommand())] == "run" {
+		command = append(append([]string{}, command...), flags...)
+	}
+
+	return command
+}
+
+// executorCommand returns the local command running command with the
+// executor if any.
+func (c *config) executorCommand(command []string) []string {
+	if c.executor != nil {
+		// the environment of the server does not reach the executor
+		if env := c.lintEnv(); len(env) > 0 {
+			command = append(append([]string{"env"}, env...), command...)
+		}
+
+		command = c.executor.wrap(command)
+	}
+
+	return command
+}
+
+// commandFunc builds the command of a run from the config it runs with, so
+// that retries can run it with another config.
+type commandFunc func(c *config) []string
+
+// lintArgs returns the commandFunc of the lint command with extra arguments.
+func lintArgs(extra ...string) commandFunc {
+	return func(c *config) []string {
+		return c.lintCommand(extra...)
+	}
+}
+
+// niceLintArgs is lintArgs for the runs with a low priority.
+func niceLintArgs(extra ...string) commandFunc {
+	return func(c *config) []string {
+		return c.niceLintCommand(extra...)
+	}
+}
+
+// workingDir returns the directory golangci-lint runs in: the workingDir
+// setting, or else the root. golangci-lint reports filenames relative to it.
+func (c *config) workingDir() string {
+	if c.explicitDir != "" {
+		return c.explicitDir
+	}
+
+	if c.rootDir != "" {
+		return c.rootDir
+	}
+
+	dir, _ := os.Getwd()
+
+	return dir
+}
+
+// rootURI returns the workspace root, falling back to the first workspace
+// folder and then to the deprecated rootPath for clients not sending rootUri.
+func (p *InitializeParams) rootURI() string {
+	switch {
+	case p.RootURI != "":
+		return p.RootURI
+	case len(p.WorkspaceFolders) > 0:
+		return p.WorkspaceFolders[0].URI
+	case p.RootPath != "":
+		return string(pathToURI(p.RootPath))
+	}
+
+	return ""
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// configErrorPattern matches the errors of golangci-lint rejecting its
+	// configuration.
+	configErrorPattern = regexp.MustCompile(`(?i)can't (read|load) config|while parsing config|unknown linters?:|error parsing regexp|validate configuration|invalid configuration`)
+
+	yamlLinePattern      = regexp.MustCompile(`yaml: line (\d+):`)
+	unknownLinterPattern = regexp.MustCompile(`unknown linters?: '([^']+)'`)
+	badRegexpPattern     = regexp.MustCompile("error parsing regexp: [^`]*`([^`]+)`")
+	logMessagePattern    = regexp.MustCompile(`msg=("(?:[^"\\]|\\.)*")`)
+
+	configErrorSource = "golangci-lint"
+)
+
+// lintConfigFile returns the golangci-lint configuration file in dir, or ""
+// if there is none.
+func lintConfigFile(dir string) string {
+	for _, name := range configNames {
+		if name = filepath.Join(dir, name); fileExists(name) {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// publishConfigError publishes a diagnostic on the golangci-lint
+// configuration file when err is golangci-lint rejecting it, and clears it
+// once err is nil.
+func (h *langHandler) publishConfigError(cfg *config, err error) {
+	name := lintConfigFile(cfg.workingDir())
+	if name == "" {
+		return
+	}
+
+	uri := pathToURI(name)
+
+	if err == nil {
+		h.pubMu.Lock()
+		published := len(h.published[uri]) > 0
+		h.pubMu.Unlock()
+
+		if published {
+			h.publish(uri, nil, []Diagnostic{})
+		}
+
+		return
+	}
+
+	message, ok := configErrorMessage(err)
+	if !ok {
+		return
+	}
+
+	text, ok := h.documentText(uri)
+	if !ok {
+		b, err := ioutil.ReadFile(name)
+		if err != nil {
+			return
+		}
+
+		text = string(b)
+	}
+
+	h.publish(uri, nil, []Diagnostic{{
+		Range:    configErrorRange(text, message),
+		Severity: DSError,
+		Source:   &configErrorSource,
+		Message:  message,
+	}})
+}
+
+// configErrorMessage returns the message of err if it is golangci-lint
+// rejecting its configuration.
+func configErrorMessage(err error) (string, bool) {
+	var toolErr *toolError
+	if !errors.As(err, &toolErr) {
+		return "", false
+	}
+
+	for _, line := range strings.Split(toolErr.Report+"\n"+toolErr.Stderr, "\n") {
+		if !configErrorPattern.MatchString(line) {
+			continue
+		}
+
+		if m := logMessagePattern.FindStringSubmatch(line); m != nil {
+			if msg, err := strconv.Unquote(m[1]); err == nil {
+				line = msg
+			}
+		}
+
+		return strings.TrimSpace(line), true
+	}
+
+	return "", false
+}
+
+// configErrorRange locates the cause of message in the configuration text,
+// falling back to the start of the file.
+func configErrorRange(text, message string) Range {
+	lines := strings.Split(text, "\n")
+
+	if m := yamlLinePattern.FindStringSubmatch(message); m != nil {
+		if n, err := strconv.Atoi(m[1]); err == nil && n > 0 && n <= len(lines) {
+			return Range{Start: Position{Line: n - 1}, End: Position{Line: n - 1, Character: utf16Offset(lines[n-1], len(lines[n-1]))}}
+		}
+	}
+
+	var word *regexp.Regexp
+
+	if m := unknownLinterPattern.FindStringSubmatch(message); m != nil {
+		word = regexp.MustCompile(`(?:^|[^\w-])(` + regexp.QuoteMeta(m[1]) + `)(?:$|[^\w-])`)
+	} else if m := badRegexpPattern.FindStringSubmatch(message); m != nil {
+		word = regexp.MustCompile(`(` + regexp.QuoteMeta(m[1]) + `)`)
+	}
+
+	if word != nil {
+		for i, line := range lines {
+			if loc := word.FindStringSubmatchIndex(line); loc != nil {
+				return Range{
+					Start: Position{Line: i, Character: utf16Offset(line, loc[2])},
+					End:   Position{Line: i, Character: utf16Offset(line, loc[3])},
+				}
+			}
+		}
+	}
+
+	return Range{}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const configFileName = "golangci-lint-langserver.yaml"
+
+var defaultCommand = []string{"golangci-lint", "run", "--out-format", "json"}
+
+// configFiles returns the server configuration files in the order they are
+// applied: the user configuration first, then the one in the workspace root.
+func configFiles(rootDir string) []string {
+	var files []string
+
+	if dir, err := os.UserConfigDir(); err == nil {
+		files = append(files, filepath.Join(dir, "golangci-lint-langserver", configFileName))
+	}
+
+	if rootDir != "" {
+		files = append(files, filepath.Join(rootDir, configFileName))
+	}
+
+	return files
+}
+
+// loadConfigFile merges the options in the YAML file name into opts, but for
+// the keys in ignored. The file uses the same keys as initializationOptions.
+// A missing file is not an error.
+func loadConfigFile(name string, opts *InitializationOptions, ignored ...string) error {
+	b, err := ioutil.ReadFile(name)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var v map[string]interface{}
+	if err := yaml.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	for _, key := range ignored {
+		delete(v, key)
+	}
+
+	// round-trip through JSON so that the json tags of the options apply
+	j, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(j, opts)
+}
+
+// loadOptions builds the effective options from the configuration files and
+// the initializationOptions sent by the client, which take precedence. The
+// command options of the configuration file in the workspace are ignored
+// unless it is trusted, and returned.
+func (h *langHandler) loadOptions(rootDir string, initOptions json.RawMessage) (InitializationOptions, *untrustedConfig) {
+	var (
+		opts      InitializationOptions
+		untrusted *untrustedConfig
+	)
+
+	files := configFiles(rootDir)
+
+	for i, name := range files {
+		var ignored []string
+
+		if rootDir != "" && i == len(files)-1 {
+			if untrusted = h.checkTrust(name); untrusted != nil {
+				ignored = untrusted.keys
+				h.logger.Printf("golangci-lint-langserver: %s is not trusted, ignoring its %s", name, strings.Join(ignored, ", "))
+			}
+		}
+
+		if err := loadConfigFile(name, &opts, ignored...); err != nil {
+			h.logger.Errorf("golangci-lint-langserver: %s: %s", name, err)
+		}
+	}
+
+	if len(initOptions) > 0 {
+		if err := json.Unmarshal(initOptions, &opts); err != nil {
+			h.logger.Errorf("golangci-lint-langserver: initializationOptions: %s", err)
+		}
+	}
+
+	if len(opts.Command) == 0 {
+		// without a command, backends having no default fail with errNoCommand
+		b, err := lookupBackend(opts.Backend)
+
+		switch {
+		case err != nil:
+			opts.Command = defaultCommand
+		case b.defaultCommand() == nil:
+			h.logger.Errorf("golangci-lint-langserver: backend %s requires a command", opts.Backend)
+		default:
+			opts.Command = b.defaultCommand()
+		}
+	}
+
+	return opts, untrusted
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+const (
+	defaultContainerRuntime = "docker"
+	defaultContainerRoot    = "/workspace"
+)
+
+type ContainerOptions struct {
+	Runtime string        `json:"runtime,omitempty"`
+	Image   string        `json:"image"`
+	Mounts  []PathMapping `json:"mounts,omitempty"`
+	Workdir string        `json:"workdir,omitempty"`
+	Args    []string      `json:"args,omitempty"`
+}
+
+type PathMapping struct {
+	Local  string `json:"local"`
+	Remote string `json:"remote"`
+}
+
+// remapPath rewrites p if it is located under from, returning the path
+// relocated under to.
+func remapPath(p, from, to string) (string, bool) {
+	if from == "" {
+		return p, false
+	}
+
+	from = filepath.Clean(from)
+
+	if p == from {
+		return to, true
+	}
+
+	if strings.HasPrefix(p, from+"/") {
+		return to + p[len(from):], true
+	}
+
+	return p, false
+}
+
+type pathMappings []PathMapping
+
+func (m pathMappings) toRemote(p string) string {
+	for _, mapping := range m {
+		if r, ok := remapPath(p, mapping.Local, mapping.Remote); ok {
+			return r
+		}
+	}
+
+	return p
+}
+
+func (m pathMappings) toLocal(p string) string {
+	for _, mapping := range m {
+		if r, ok := remapPath(p, mapping.Remote, mapping.Local); ok {
+			return r
+		}
+	}
+
+	return p
+}
+
+// remapArg translates an argument that is either a path or a --flag=path pair.
+func (m pathMappings) remapArg(arg string) string {
+	if i := strings.Index(arg, "="); i >= 0 && strings.HasPrefix(arg, "-") {
+		return arg[:i+1] + m.toRemote(arg[i+1:])
+	}
+
+	return m.toRemote(arg)
+}
+
+type containerExecutor struct {
+	// orig are the options as configured, before defaults were applied.
+	orig   ContainerOptions
+	opts   ContainerOptions
+	mounts pathMappings
+}
+
+func newContainerExecutor(opts ContainerOptions, rootDir string) *containerExecutor {
+	orig := opts
+
+	if opts.Runtime == "" {
+		opts.Runtime = defaultContainerRuntime
+	}
+
+	mounts := pathMappings(opts.Mounts)
+	if len(mounts) == 0 && rootDir != "" {
+		mounts = pathMappings{{Local: rootDir, Remote: defaultContainerRoot}}
+	}
+
+	if opts.Workdir == "" {
+		opts.Workdir = mounts.toRemote(rootDir)
+	}
+
+	return &containerExecutor{orig: orig, opts: opts, mounts: mounts}
+}
+
+// wrap returns the command line that runs command inside the container.
+func (e *containerExecutor) wrap(command []string) []string {
+	args := []string{e.opts.Runtime, "run", "--rm", "-i"}
+
+	for _, mount := range e.mounts {
+		args = append(args, "-v", mount.Local+":"+mount.Remote)
+	}
+
+	if e.opts.Workdir != "" {
+		args = append(args, "-w", e.opts.Workdir)
+	}
+
+	args = append(args, e.opts.Args...)
+	args = append(args, e.opts.Image)
+
+	for _, arg := range command {
+		args = append(args, e.mounts.remapArg(arg))
+	}
+
+	return args
+}
+
+func (e *containerExecutor) toLocal(path string) string {
+	return e.mounts.toLocal(path)
+}
+
+func (e *containerExecutor) workdir() string {
+	return e.opts.Workdir
+}
+
+func (e *containerExecutor) forDir(dir string) executor {
+	return newContainerExecutor(e.orig, dir)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// doctor checks the environment the server runs golangci-lint in for the
+// workspace rooted at dir and writes a report to w. It returns the exit code
+// of the doctor subcommand: 1 if a check failed.
+func doctor(w io.Writer, dir string) int {
+	failed := false
+
+	report := func(ok bool, format string, args ...interface{}) {
+		status := "ok  "
+		if !ok {
+			status, failed = "FAIL", true
+		}
+
+		fmt.Fprintf(w, "[%s] %s\n", status, fmt.Sprintf(format, args...))
+	}
+
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+
+	fmt.Fprintf(w, "golangci-lint-langserver doctor for %s\n\n", dir)
+
+	for _, name := range configFiles(dir) {
+		var opts InitializationOptions
+
+		switch err := loadConfigFile(name, &opts); {
+		case err != nil:
+			report(false, "config file %s: %s", name, err)
+		case fileExists(name):
+			report(true, "config file %s is valid", name)
+		default:
+			report(true, "config file %s does not exist", name)
+		}
+	}
+
+	if out, err := exec.Command("go", "version").Output(); err != nil {
+		report(false, "go toolchain: %s", err)
+	} else {
+		report(true, "go toolchain: %s", strings.TrimSpace(string(out)))
+	}
+
+	var logs bytes.Buffer
+
+	h := newLangHandler(newStdLogger(&logs, 0, levelError, false), newSharedState())
+	defer h.close()
+
+	cfg := h.newConfig(nil, &InitializeParams{RootURI: string(pathToURI(dir))})
+	h.setConfig(cfg)
+
+	if logs.Len() > 0 {
+		report(false, "configuration: %s", strings.TrimSpace(logs.String()))
+	}
+
+	if u := cfg.untrusted; u != nil {
+		report(true, "config file %s is not trusted, ignoring its %s", u.name, strings.Join(u.keys, ", "))
+	}
+
+	if cfg.fallback != "" {
+		report(false, "%s not found in PATH, go vet would be used instead", cfg.fallback)
+	} else if len(cfg.command) == 0 {
+		report(false, "backend %s requires a command", cfg.backendName)
+	} else if cfg.executor == nil {
+		if path, err := exec.LookPath(cfg.command[0]); err != nil {
+			report(false, "%s: %s", cfg.command[0], err)
+		} else {
+			report(true, "%s found at %s", cfg.command[0], path)
+		}
+	}
+
+	if cfg.backendName == defaultBackend && cfg.fallback == "" {
+		if info, err := cfg.golangciLintVersion(); err != nil {
+			report(false, "golangci-lint version: %s", err)
+		} else {
+			report(true, "golangci-lint version %s", info.Version)
+
+			cfg.version = info.Version
+
+			for _, warning := range checkCompatibility(info, readRequirements(dir)) {
+				report(false, "%s", warning)
+			}
+		}
+	}
+
+	command := cfg.lintCommand()
+	fmt.Fprintf(w, "\nrunning %s\n", strings.Join(command, " "))
+
+	if result, err := h.run(h.ctx, cfg, command); err != nil {
+		report(false, "sample run: %s", err)
+	} else {
+		report(true, "sample run: output parsed, %d issues", len(result.Issues))
+	}
+
+	if failed {
+		return 1
+	}
+
+	return 0
+}
+
+func fileExists(name string) bool {
+	_, err := os.Stat(name)
+
+	return err == nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import "io/ioutil"
+
+type document struct {
+	uri        DocumentURI
+	languageID string
+	version    int
+	text       string
+	// saved is the content sent with the last didSave, if any.
+	saved *string
+}
+
+func (h *langHandler) openDocument(item TextDocumentItem) {
+	h.docsMu.Lock()
+	defer h.docsMu.Unlock()
+
+	h.docs[item.URI] = &document{
+		uri:        item.URI,
+		languageID: item.LanguageID,
+		version:    item.Version,
+		text:       item.Text,
+	}
+}
+
+// changeDocument records the content of a changed document. Only full
+// content changes are expected, as incremental ones are never requested.
+func (h *langHandler) changeDocument(params *DidChangeTextDocumentParams) {
+	h.docsMu.Lock()
+	defer h.docsMu.Unlock()
+
+	doc, ok := h.docs[params.TextDocument.URI]
+	if !ok {
+		return
+	}
+
+	doc.version = params.TextDocument.Version
+	doc.saved = nil
+
+	for _, change := range params.ContentChanges {
+		if change.Range == nil {
+			doc.text = change.Text
+		}
+	}
+}
+
+// saveDocument records the content uri was saved with, which is also the
+// content of its buffer.
+func (h *langHandler) saveDocument(uri DocumentURI, text string) {
+	h.docsMu.Lock()
+	defer h.docsMu.Unlock()
+
+	if doc, ok := h.docs[uri]; ok {
+		doc.text = text
+		doc.saved = &text
+	}
+}
+
+// rewritten reports whether the content of uri on disk differs from the one
+// it was last saved with, as when a formatter rewrites files after saves.
+func (h *langHandler) rewritten(uri DocumentURI) bool {
+	h.docsMu.Lock()
+	doc, ok := h.docs[uri]
+
+	var saved *string
+	if ok {
+		saved = doc.saved
+	}
+	h.docsMu.Unlock()
+
+	if saved == nil {
+		return false
+	}
+
+	content, err := ioutil.ReadFile(uriToPath(string(uri)))
+
+	return err == nil && string(content) != *saved
+}
+
+func (h *langHandler) closeDocument(uri DocumentURI) {
+	h.docsMu.Lock()
+	defer h.docsMu.Unlock()
+
+	delete(h.docs, uri)
+}
+
+// documentText returns the content of uri if it is open.
+func (h *langHandler) documentText(uri DocumentURI) (string, bool) {
+	h.docsMu.Lock()
+	defer h.docsMu.Unlock()
+
+	doc, ok := h.docs[uri]
+	if !ok {
+		return "", false
+	}
+
+	return doc.text, true
+}
+
+// documentVersions returns the current versions of the open documents among
+// uris.
+func (h *langHandler) documentVersions(uris []DocumentURI) map[DocumentURI]*int {
+	h.docsMu.Lock()
+	defer h.docsMu.Unlock()
+
+	versions := make(map[DocumentURI]*int, len(uris))
+
+	for _, uri := range uris {
+		if doc, ok := h.docs[uri]; ok {
+			version := doc.version
+			versions[uri] = &version
+		}
+	}
+
+	return versions
+}
+
+// diskVersions returns the current versions of the open documents among uris
+// whose content is that of their file. golangci-lint reads the files, so that
+// its diagnostics for a buffer with unsaved changes are for no version of it.
+func (h *langHandler) diskVersions(uris []DocumentURI) map[DocumentURI]*int {
+	versions := h.documentVersions(uris)
+
+	for uri := range versions {
+		if !h.matchesDisk(uri) {
+			delete(versions, uri)
+		}
+	}
+
+	return versions
+}
+
+// openDocuments returns the URIs of the documents opened in the client.
+func (h *langHandler) openDocuments() []DocumentURI {
+	h.docsMu.Lock()
+	defer h.docsMu.Unlock()
+
+	uris := make([]DocumentURI, 0, len(h.docs))
+	for uri := range h.docs {
+		uris = append(uris, uri)
+	}
+
+	return uris
+}
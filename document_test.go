@@ -0,0 +1,53 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRewritten(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golangci-lint-langserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	name := filepath.Join(dir, "main.go")
+	uri := pathToURI(name)
+
+	h := &langHandler{docs: make(map[DocumentURI]*document)}
+	h.openDocument(TextDocumentItem{URI: uri, LanguageID: "go", Version: 1, Text: "package main\n"})
+
+	if err := ioutil.WriteFile(name, []byte("package main\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if h.rewritten(uri) {
+		t.Error("rewritten before any save")
+	}
+
+	h.saveDocument(uri, "package main\n")
+
+	if h.rewritten(uri) {
+		t.Error("rewritten while the file has the saved content")
+	}
+
+	if err := ioutil.WriteFile(name, []byte("package main\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if !h.rewritten(uri) {
+		t.Error("not rewritten once the file changed after the save")
+	}
+
+	h.changeDocument(&DidChangeTextDocumentParams{
+		TextDocument:   VersionedTextDocumentIdentifier{URI: uri, Version: 2},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: "package main\n\n"}},
+	})
+
+	if h.rewritten(uri) {
+		t.Error("rewritten after a change following the save")
+	}
+}
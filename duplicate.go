@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DuplicatesOptions merge the issues several linters report on the same
+// range with the same message, or on the same line with checks listed as
+// equivalent.
+type DuplicatesOptions struct {
+	// Equivalent lists groups of checks reporting the same problem, each
+	// one a linter or a linter and a check, as in "staticcheck:SA4000".
+	Equivalent [][]string `json:"equivalent,omitempty"`
+}
+
+// messagePrefixPattern matches the check IDs and rule names that linters put
+// in front of their messages, as in "SA4000: " or "dupSubExpr: ".
+var messagePrefixPattern = regexp.MustCompile(`^[\w-]+: `)
+
+// normalizeMessage returns an issue text without what differs between
+// linters reporting the same problem.
+func normalizeMessage(text string) string {
+	text = messagePrefixPattern.ReplaceAllString(text, "")
+	text = strings.Join(strings.Fields(strings.ToLower(text)), " ")
+
+	return strings.TrimRight(text, ".!")
+}
+
+// equivalentChecks maps the checks of the equivalent groups to their index.
+func (o *DuplicatesOptions) equivalentChecks() map[string]int {
+	groups := make(map[string]int)
+
+	for i, group := range o.Equivalent {
+		for _, check := range group {
+			groups[check] = i
+		}
+	}
+
+	return groups
+}
+
+// mergeDuplicates merges the diagnostics of a file reported by several
+// linters into the first one, which gets the most severe severity and names
+// the other linters in its message, keeping its own as the source.
+func (c *config) mergeDuplicates(diagnostics []Diagnostic) []Diagnostic {
+	if c.duplicates == nil || len(diagnostics) < 2 {
+		return diagnostics
+	}
+
+	groups := c.duplicates.equivalentChecks()
+	first := make(map[string]int, len(diagnostics))
+	merged := diagnostics[:0]
+
+	for _, d := range diagnostics {
+		data, ok := d.Data.(*diagnosticData)
+		if !ok {
+			merged = append(merged, d)
+
+			continue
+		}
+
+		keys := duplicateKeys(&d, data, groups)
+
+		j, dup := -1, false
+		for _, key := range keys {
+			if j, dup = first[key]; dup {
+				break
+			}
+		}
+
+		// issues of a single linter are distinct even on the same line
+		if dup && merged[j].Data.(*diagnosticData).reportedBy(data.Linter) {
+			merged = append(merged, d)
+
+			continue
+		}
+
+		if !dup {
+			for _, key := range keys {
+				first[key] = len(merged)
+			}
+
+			merged = append(merged, d)
+
+			continue
+		}
+
+		survivor := &merged[j]
+		sd := survivor.Data.(*diagnosticData)
+
+		if len(sd.Linters) == 0 {
+			sd.Linters = []string{sd.Linter}
+		}
+
+		sd.Linters = append(sd.Linters, data.Linter)
+
+		if d.Severity < survivor.Severity {
+			survivor.Severity = d.Severity
+		}
+	}
+
+	for i := range merged {
+		if data, ok := merged[i].Data.(*diagnosticData); ok && len(data.Linters) > 1 {
+			merged[i].Message += fmt.Sprintf(" (also reported by %s)", strings.Join(data.Linters[1:], ", "))
+		}
+	}
+
+	return merged
+}
+
+// duplicateKeys returns the keys under which the diagnostic d is a duplicate
+// of another: its range and normalized message, and its line and group of
+// equivalent checks if any.
+func duplicateKeys(d *Diagnostic, data *diagnosticData, groups map[string]int) []string {
+	r := d.Range
+	keys := []string{fmt.Sprintf("%d:%d-%d:%d %s", r.Start.Line, r.Start.Character, r.End.Line, r.End.Character, normalizeMessage(data.Text))}
+
+	check := data.Linter
+	if m := messagePrefixPattern.FindString(data.Text); m != "" {
+		check += ":" + strings.TrimSuffix(m, ": ")
+	}
+
+	if i, ok := groups[check]; ok {
+		keys = append(keys, fmt.Sprintf("%d #%d", r.Start.Line, i))
+	} else if i, ok := groups[data.Linter]; ok {
+		keys = append(keys, fmt.Sprintf("%d #%d", r.Start.Line, i))
+	}
+
+	return keys
+}
+
+// diagnosticLinters returns the linters reporting the issue of d, which are
+// several for merged duplicates.
+func diagnosticLinters(d *Diagnostic) []string {
+	if data, ok := d.Data.(*diagnosticData); ok && len(data.Linters) > 0 {
+		return data.Linters
+	}
+
+	if d.Source != nil {
+		return []string{*d.Source}
+	}
+
+	return nil
+}
+
+// reportedBy reports whether linter reported the issue of data.
+func (data *diagnosticData) reportedBy(linter string) bool {
+	if data.Linter == linter {
+		return true
+	}
+
+	for _, l := range data.Linters {
+		if l == linter {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeDuplicates(t *testing.T) {
+	diagnostic := func(linter, text string, line int, severity DiagnosticSeverity) Diagnostic {
+		source := linter
+
+		return Diagnostic{
+			Range:    Range{Start: Position{Line: line}, End: Position{Line: line, Character: 4}},
+			Severity: severity,
+			Source:   &source,
+			Message:  linter + ": " + text,
+			Data:     &diagnosticData{Linter: linter, Text: text},
+		}
+	}
+
+	c := &config{duplicates: &DuplicatesOptions{Equivalent: [][]string{{"gocritic:dupSubExpr", "staticcheck:SA4000"}}}}
+
+	merged := c.mergeDuplicates([]Diagnostic{
+		diagnostic("gocritic", "dupSubExpr: suspicious identical LHS and RHS", 3, DSWarning),
+		diagnostic("revive", "Suspicious identical LHS and RHS.", 3, DSError),
+		diagnostic("errcheck", "Error return value is not checked", 5, DSWarning),
+		diagnostic("staticcheck", "SA4000: identical expressions on the left and right side", 3, DSWarning),
+	})
+
+	if len(merged) != 2 {
+		t.Fatalf("merged into %d diagnostics, want 2", len(merged))
+	}
+
+	d := merged[0]
+	if *d.Source != "gocritic" {
+		t.Errorf("source = %q, want the first linter", *d.Source)
+	}
+
+	if want := "gocritic: dupSubExpr: suspicious identical LHS and RHS (also reported by revive, staticcheck)"; d.Message != want {
+		t.Errorf("message = %q, want %q", d.Message, want)
+	}
+
+	if d.Severity != DSError {
+		t.Errorf("severity = %d, want the most severe %d", d.Severity, DSError)
+	}
+
+	if got := diagnosticLinters(&d); !reflect.DeepEqual(got, []string{"gocritic", "revive", "staticcheck"}) {
+		t.Errorf("linters = %q", got)
+	}
+
+	if merged[1].Message != "errcheck: Error return value is not checked" {
+		t.Errorf("distinct diagnostic changed: %q", merged[1].Message)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// EffectiveConfigParams are the params of golangci-lint/effectiveConfig. URI
+// selects the folder and module settings of a file, the root otherwise.
+type EffectiveConfigParams struct {
+	URI DocumentURI `json:"uri,omitempty"`
+}
+
+// EffectiveConfigResult is the response of golangci-lint/effectiveConfig:
+// the configuration golangci-lint uses for a file, as it reports it, and what
+// the server adds to it.
+type EffectiveConfigResult struct {
+	WorkingDir       string `json:"workingDir"`
+	WorkingDirSource string `json:"workingDirSource"`
+	// ConfigFile is the file golangci-lint config path reports, and Config
+	// its content.
+	ConfigFile string `json:"configFile,omitempty"`
+	Config     string `json:"config,omitempty"`
+	// Valid and Errors are the result of golangci-lint config verify, unset
+	// when it could not run.
+	Valid   *bool  `json:"valid,omitempty"`
+	Errors  string `json:"errors,omitempty"`
+	Version string `json:"version,omitempty"`
+	// Command and Env are those of golangci-lint run, with the flags the
+	// server adds.
+	Command []string       `json:"command"`
+	Env     []string       `json:"env,omitempty"`
+	Linters *LintersResult `json:"linters,omitempty"`
+	Filters ServerFilters  `json:"filters"`
+}
+
+// ServerFilters are the settings of the server hiding or changing issues after
+// golangci-lint reports them.
+type ServerFilters struct {
+	LinterToggles    map[string]bool    `json:"linterToggles,omitempty"`
+	MinSeverity      DiagnosticSeverity `json:"minSeverity,omitempty"`
+	Rules            []Rule             `json:"rules,omitempty"`
+	ExcludePaths     []string           `json:"excludePaths,omitempty"`
+	IncludeGenerated bool               `json:"includeGenerated,omitempty"`
+}
+
+func (h *langHandler) handleEffectiveConfig(ctx context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (result interface{}, err error) {
+	var params EffectiveConfigParams
+	if req.Params != nil {
+		if err := json.Unmarshal(*req.Params, &params); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg := h.config()
+	if params.URI != "" {
+		cfg = cfg.forFile(uriToPath(string(params.URI)))
+	}
+
+	if cfg.backendName != defaultBackend || cfg.fallback != "" {
+		return nil, errNoEffectiveConfig
+	}
+
+	res := EffectiveConfigResult{
+		WorkingDir:       cfg.workingDir(),
+		WorkingDirSource: cfg.workingDirSource(),
+		Version:          cfg.version,
+		Command:          cfg.lintCommand(),
+		Env:              cfg.lintEnv(),
+		Filters:          cfg.filters(),
+	}
+
+	command := append(append([]string{}, cfg.binaryCommand()...), "config")
+	flags := configFlags(cfg.command[len(cfg.binaryCommand()):])
+
+	// golangci-lint config path fails when there is no configuration
+	if out, err := h.output(ctx, cfg, append(append(command, "path"), flags...)); err == nil {
+		res.ConfigFile = strings.TrimSpace(string(out))
+		res.Config = h.configText(cfg, res.ConfigFile)
+	}
+
+	// golangci-lint config verify appeared in v1.57.0
+	if cfg.version == "" || compareVersions(cfg.version, "1.57.0") >= 0 {
+		verify := append(append(command, "verify"), flags...)
+
+		if _, err := h.output(ctx, cfg, verify); err == nil {
+			valid := true
+			res.Valid = &valid
+		} else if errs, ok := verifyErrors(err); ok {
+			valid := false
+			res.Valid, res.Errors = &valid, errs
+		}
+	}
+
+	if linters, err := h.listLinters(ctx, cfg); err == nil {
+		res.Linters = &linters
+	}
+
+	return res, nil
+}
+
+// configFlags returns the flags of golangci-lint run args selecting the
+// configuration, which the config subcommands understand too.
+func configFlags(args []string) []string {
+	var flags []string
+
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--no-config":
+			flags = append(flags, arg)
+		case (arg == "-c" || arg == "--config") && i+1 < len(args):
+			flags = append(flags, arg, args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--config="), strings.HasPrefix(arg, "-c="):
+			flags = append(flags, arg)
+		}
+	}
+
+	return flags
+}
+
+// configText returns the content of the configuration file name, preferring
+// the content of its open document. name is relative to the working
+// directory when it does not exist as reported.
+func (h *langHandler) configText(cfg *config, name string) string {
+	if name == "" {
+		return ""
+	}
+
+	name = cfg.issuePath(name)
+
+	if text, ok := h.documentText(pathToURI(name)); ok {
+		return text
+	}
+
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		return ""
+	}
+
+	return string(b)
+}
+
+// verifyErrors returns the reasons golangci-lint config verify rejected the
+// configuration, and false if it failed otherwise, as when the version has no
+// such command.
+func verifyErrors(err error) (string, bool) {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || strings.Contains(err.Error(), "unknown command") {
+		return "", false
+	}
+
+	// output appends the standard error to the exit status
+	message := err.Error()
+	if i := strings.Index(message, exitErr.Error()+": "); i >= 0 {
+		return strings.TrimSpace(message[i+len(exitErr.Error())+2:]), true
+	}
+
+	return "", true
+}
+
+// filters returns the settings of c filtering issues.
+func (c *config) filters() ServerFilters {
+	filters := ServerFilters{
+		LinterToggles:    c.linterToggles,
+		MinSeverity:      c.minSeverity,
+		ExcludePaths:     c.excludePaths,
+		IncludeGenerated: c.includeGenerated,
+	}
+
+	for _, r := range c.rules {
+		rule := Rule{Pattern: r.pattern.String(), Linter: r.linter, Severity: severityName(r.severity)}
+		if r.ignore {
+			rule.Action = ruleActionIgnore
+		}
+
+		filters.Rules = append(filters.Rules, rule)
+	}
+
+	return filters
+}
@@ -0,0 +1,15 @@
+package main
+
+import "errors"
+
+var (
+	errUnknownVersion = errors.New("unknown golangci-lint version")
+	errBinaryNotFound = errors.New("binary not found in archive")
+	errNoCommand      = errors.New("no lint command configured")
+	errCancelled      = errors.New("run cancelled")
+	errNoLinterList   = errors.New("linters are only listed by golangci-lint")
+	errNoStats        = errors.New("stats are not enabled")
+	errNoFixture      = errors.New("fixtures only record lint runs")
+
+	errNoEffectiveConfig = errors.New("the effective configuration is only reported by golangci-lint")
+)
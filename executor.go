@@ -0,0 +1,15 @@
+package main
+
+// executor runs golangci-lint somewhere other than the local machine, such as
+// inside a container or on a remote host.
+type executor interface {
+	// wrap returns the local command line that runs command.
+	wrap(command []string) []string
+	// toLocal translates a path reported by golangci-lint to a local path.
+	toLocal(path string) string
+	// workdir returns the remote directory golangci-lint runs in.
+	workdir() string
+	// forDir returns an executor running golangci-lint in the remote
+	// directory corresponding to the local directory dir.
+	forDir(dir string) executor
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// exit codes of golangci-lint
+const (
+	exitCodeSuccess       = 0
+	exitCodeIssuesFound   = 1
+	exitCodeWarningInTest = 2
+	exitCodeNoGoFiles     = 5
+)
+
+type runKind int
+
+const (
+	runClean runKind = iota
+	runIssues
+	runFailed
+)
+
+func classifyExitCode(code, issuesExitCode int) runKind {
+	switch code {
+	case exitCodeSuccess, issuesExitCode, exitCodeIssuesFound, exitCodeWarningInTest:
+		// issues may be reported even when exiting successfully with --issues-exit-code=0
+		return runIssues
+	case exitCodeNoGoFiles:
+		return runClean
+	default:
+		return runFailed
+	}
+}
+
+// issuesExitCode returns the value of --issues-exit-code in the lint command.
+func (c *config) issuesExitCode() int {
+	for i, arg := range c.command {
+		var value string
+
+		switch {
+		case arg == "--issues-exit-code" && i+1 < len(c.command):
+			value = c.command[i+1]
+		case strings.HasPrefix(arg, "--issues-exit-code="):
+			value = strings.TrimPrefix(arg, "--issues-exit-code=")
+		default:
+			continue
+		}
+
+		if code, err := strconv.Atoi(value); err == nil {
+			return code
+		}
+	}
+
+	return exitCodeIssuesFound
+}
+
+// toolError is returned when the linter itself failed, as opposed to having
+// found issues.
+type toolError struct {
+	Tool     string
+	ExitCode int
+	Report   string
+	Stderr   string
+}
+
+func (e *toolError) Error() string {
+	msg := e.Report
+	if msg == "" {
+		msg = strings.TrimSpace(e.Stderr)
+	}
+
+	return fmt.Sprintf("%s failed with exit code %d: %s", e.Tool, e.ExitCode, msg)
+}
+
+// reportFailure logs err and shows tool failures and missing commands to the
+// user. The same failure is shown only once until a run succeeds.
+func (h *langHandler) reportFailure(err error) {
+	h.logger.Errorf("golangci-lint-langserver: %s", err)
+
+	var toolErr *toolError
+	if !errors.As(err, &toolErr) && !errors.Is(err, errNoCommand) || err.Error() == h.lastFailure {
+		return
+	}
+
+	h.lastFailure = err.Error()
+	h.showMessage(MTError, err.Error())
+}
@@ -0,0 +1,52 @@
+package main
+
+import "testing"
+
+func TestClassifyExitCode(t *testing.T) {
+	tests := []struct {
+		name           string
+		code           int
+		issuesExitCode int
+		want           runKind
+	}{
+		{name: "success", code: exitCodeSuccess, issuesExitCode: exitCodeIssuesFound, want: runIssues},
+		{name: "issues found", code: exitCodeIssuesFound, issuesExitCode: exitCodeIssuesFound, want: runIssues},
+		{name: "warning in test", code: exitCodeWarningInTest, issuesExitCode: exitCodeIssuesFound, want: runIssues},
+		{name: "custom issues exit code", code: 42, issuesExitCode: 42, want: runIssues},
+		{name: "no go files", code: exitCodeNoGoFiles, issuesExitCode: exitCodeIssuesFound, want: runClean},
+		{name: "failure", code: 3, issuesExitCode: exitCodeIssuesFound, want: runFailed},
+		{name: "timeout", code: exitCodeTimeout, issuesExitCode: exitCodeIssuesFound, want: runFailed},
+		{name: "killed", code: -1, issuesExitCode: exitCodeIssuesFound, want: runFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyExitCode(tt.code, tt.issuesExitCode); got != tt.want {
+				t.Errorf("classifyExitCode(%d, %d) = %v, want %v", tt.code, tt.issuesExitCode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssuesExitCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		command []string
+		want    int
+	}{
+		{name: "default", command: defaultCommand, want: exitCodeIssuesFound},
+		{name: "separate value", command: []string{"golangci-lint", "run", "--issues-exit-code", "7"}, want: 7},
+		{name: "joined value", command: []string{"golangci-lint", "run", "--issues-exit-code=0"}, want: 0},
+		{name: "missing value", command: []string{"golangci-lint", "run", "--issues-exit-code"}, want: exitCodeIssuesFound},
+		{name: "invalid value", command: []string{"golangci-lint", "run", "--issues-exit-code=x"}, want: exitCodeIssuesFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &config{command: tt.command}
+			if got := c.issuesExitCode(); got != tt.want {
+				t.Errorf("issuesExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
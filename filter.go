@@ -0,0 +1,133 @@
+package main
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// linterFlags returns the golangci-lint flags enabling and disabling linters
+// as configured, so that filtered linters do not even run.
+func (c *config) linterFlags() []string {
+	var flags []string
+
+	// golangci-lint rejects a linter both enabled and disabled, so the
+	// session toggles replace the configured flags for their linters
+	for _, linter := range c.enabledLinters {
+		if _, ok := c.linterToggles[linter]; !ok {
+			flags = append(flags, "--enable", linter)
+		}
+	}
+
+	for _, linter := range c.disabledLinters {
+		if _, ok := c.linterToggles[linter]; !ok {
+			flags = append(flags, "--disable", linter)
+		}
+	}
+
+	if _, ok := c.linterToggles["nolintlint"]; c.unusedNolint && !ok {
+		flags = append(flags, "--enable", "nolintlint")
+	}
+
+	toggled := make([]string, 0, len(c.linterToggles))
+	for linter := range c.linterToggles {
+		toggled = append(toggled, linter)
+	}
+
+	sort.Strings(toggled)
+
+	for _, linter := range toggled {
+		if c.linterToggles[linter] {
+			flags = append(flags, "--enable", linter)
+		} else {
+			flags = append(flags, "--disable", linter)
+		}
+	}
+
+	return flags
+}
+
+// linterAllowed reports whether the issues of linter should be published.
+func (c *config) linterAllowed(linter string) bool {
+	if enabled, ok := c.linterToggles[linter]; ok {
+		return enabled
+	}
+
+	for _, l := range c.disabledLinters {
+		if l == linter {
+			return false
+		}
+	}
+
+	if len(c.enabledLinters) == 0 || c.unusedNolint && linter == "nolintlint" {
+		return true
+	}
+
+	for _, l := range c.enabledLinters {
+		if l == linter {
+			return true
+		}
+	}
+
+	return false
+}
+
+// excluded reports whether no diagnostics should be published for uri.
+func (h *langHandler) excluded(cfg *config, uri DocumentURI) bool {
+	return cfg.excludedPath(uriToPath(string(uri))) || !cfg.includeGenerated && h.isGeneratedFile(uri)
+}
+
+// ignored reports whether uri matches one of the ignoreFiles entries, which
+// are file or directory URIs, or globs matched against the path relative to
+// the workspace root. Ignored files are never linted nor published.
+func (c *config) ignored(uri DocumentURI) bool {
+	if len(c.ignoreFiles) == 0 {
+		return false
+	}
+
+	path := normalizePath(uriToPath(string(uri)))
+
+	rel, err := filepath.Rel(c.workspaceRoot(), uriToPath(string(uri)))
+	if err != nil {
+		rel = ""
+	}
+
+	rel = filepath.ToSlash(rel)
+
+	for _, entry := range c.ignoreFiles {
+		if strings.HasPrefix(entry, "file://") {
+			dir := normalizePath(uriToPath(entry))
+			if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+				return true
+			}
+
+			continue
+		}
+
+		// a pattern matching a directory ignores the files below it
+		if rel != "" && (matchGlob(entry, rel) || matchGlob(entry+"/**", rel)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// excludedPath reports whether path matches one of the excludePaths globs,
+// which are matched against the path relative to the workspace root.
+func (c *config) excludedPath(path string) bool {
+	rel, err := filepath.Rel(c.workspaceRoot(), path)
+	if err != nil {
+		return false
+	}
+
+	rel = filepath.ToSlash(rel)
+
+	for _, pattern := range c.excludePaths {
+		if matchGlob(pattern, rel) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExcludedPathForFile(t *testing.T) {
+	root, err := ioutil.TempDir("", "golangci-lint-langserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	modDir := filepath.Join(root, "mod")
+	if err := os.MkdirAll(filepath.Join(modDir, "gen"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(modDir, "go.mod"), []byte("module example.com/mod\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(modDir, "gen", "types.go")
+
+	tests := []struct {
+		pattern string
+		want    bool
+	}{
+		{pattern: "mod/gen/**", want: true},
+		{pattern: "**/gen/*.go", want: true},
+		{pattern: "gen/**", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			cfg := (&config{rootDir: root, excludePaths: []string{tt.pattern}}).forFile(path)
+			if cfg.rootDir != modDir {
+				t.Fatalf("forFile() runs in %s, want %s", cfg.rootDir, modDir)
+			}
+
+			if got := cfg.excludedPath(path); got != tt.want {
+				t.Errorf("excludedPath() = %v, want %v", got, tt.want)
+			}
+
+			uri := pathToURI(path)
+			if got := (&config{rootDir: root, ignoreFiles: []string{tt.pattern}}).forFile(path).ignored(uri); got != tt.want {
+				t.Errorf("ignored() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
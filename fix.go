@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+)
+
+// fileFix holds the edits of the fix of an issue in one file. The fixes of
+// some issues span several files.
+type fileFix struct {
+	// URI is empty for the file of the issue, which the client may know by
+	// another URI than the one derived from the path golangci-lint reports.
+	URI   DocumentURI `json:"uri,omitempty"`
+	Edits []TextEdit  `json:"edits"`
+}
+
+// fixedDocuments returns uri and the other documents that the fixes of
+// diagnostics edit.
+func fixedDocuments(uri DocumentURI, diagnostics []Diagnostic) []DocumentURI {
+	uris := []DocumentURI{uri}
+	seen := map[DocumentURI]bool{uri: true}
+
+	for i := range diagnostics {
+		data, ok := decodeData(&diagnostics[i])
+		if !ok {
+			continue
+		}
+
+		for _, f := range data.Fix {
+			if f.URI != "" && !seen[f.URI] {
+				seen[f.URI] = true
+				uris = append(uris, f.URI)
+			}
+		}
+	}
+
+	return uris
+}
+
+// fixEdit returns the edit applying fixes to uri, the document of their
+// issue, and to the other files they touch. Clients supporting document
+// changes get the version of every open document, so that they refuse to
+// apply the edit to a document changed meanwhile. It fails when another open
+// document differs from the file golangci-lint read.
+func (h *langHandler) fixEdit(cfg *config, uri DocumentURI, fixes []fileFix) (*WorkspaceEdit, error) {
+	edits := make(map[DocumentURI][]TextEdit)
+
+	for _, f := range fixes {
+		target := f.URI
+		if target == "" {
+			target = uri
+		} else if !h.matchesDisk(target) {
+			return nil, fmt.Errorf("%s has unsaved changes", uriToPath(string(target)))
+		}
+
+		edits[target] = append(edits[target], f.Edits...)
+	}
+
+	if !cfg.documentChanges {
+		return &WorkspaceEdit{Changes: edits}, nil
+	}
+
+	uris := make([]DocumentURI, 0, len(edits))
+	for u := range edits {
+		uris = append(uris, u)
+	}
+
+	sort.Slice(uris, func(i, j int) bool { return uris[i] < uris[j] })
+
+	versions := h.documentVersions(uris)
+	edit := &WorkspaceEdit{}
+
+	for _, u := range uris {
+		edit.DocumentChanges = append(edit.DocumentChanges, TextDocumentEdit{
+			TextDocument: OptionalVersionedTextDocumentIdentifier{URI: u, Version: versions[u]},
+			Edits:        edits[u],
+		})
+	}
+
+	return edit, nil
+}
+
+// matchesDisk reports whether uri is not open or has the content of its file.
+func (h *langHandler) matchesDisk(uri DocumentURI) bool {
+	text, ok := h.documentText(uri)
+	if !ok {
+		return true
+	}
+
+	b, err := ioutil.ReadFile(uriToPath(string(uri)))
+
+	return err == nil && string(b) == text
+}
+
+// fixAction returns the code action applying the fix of data.
+func (h *langHandler) fixAction(cfg *config, uri DocumentURI, d *Diagnostic, data *diagnosticData) (*CodeAction, bool) {
+	if len(data.Fix) == 0 {
+		return nil, false
+	}
+
+	edit, err := h.fixEdit(cfg, uri, data.Fix)
+	if err != nil {
+		h.logger.Debugf("golangci-lint-langserver: fix action: %s", err)
+
+		return nil, false
+	}
+
+	title := fmt.Sprintf("Fix with %s", data.Linter)
+	if formatters[data.Linter] {
+		title = fmt.Sprintf("Format with %s", data.Linter)
+	}
+
+	return &CodeAction{
+		Title:       title,
+		Kind:        codeActionKindQuickFix,
+		Diagnostics: []Diagnostic{*d},
+		Edit:        edit,
+	}, true
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	fixtureOutput = "output.json"
+	fixtureRun    = "run.json"
+)
+
+var (
+	errUnknownBackendFlag = errors.New("unknown backend, expected fixture:DIR")
+	errNotFixtureDir      = errors.New("fixture is not a directory")
+)
+
+// fixtureSet replays the output of golangci-lint recorded in a directory
+// instead of running it, for reproducible benchmarks and bug reports. The
+// directory holds output.json, the standard output of golangci-lint run, and
+// optionally run.json, telling how the run ended. A subdirectory named after a
+// working directory relative to the root holds the runs of that directory.
+type fixtureSet struct {
+	dir string
+}
+
+// fixtureRunInfo is the content of run.json.
+type fixtureRunInfo struct {
+	ExitCode int    `json:"exitCode,omitempty"`
+	Stderr   string `json:"stderr,omitempty"`
+	// Delay is how long the run takes, as in "1.5s".
+	Delay string `json:"delay,omitempty"`
+}
+
+// parseBackendFlag returns the fixtures selected by the -backend flag, nil for
+// the configured backends.
+func parseBackendFlag(value string) (*fixtureSet, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	dir := strings.TrimPrefix(value, "fixture:")
+	if dir == value || dir == "" {
+		return nil, fmt.Errorf("%w: %q", errUnknownBackendFlag, value)
+	}
+
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%w: %s", errNotFixtureDir, dir)
+	}
+
+	return &fixtureSet{dir: dir}, nil
+}
+
+// path returns the file name recorded for the working directory of cfg,
+// falling back to the one of the fixture directory.
+func (f *fixtureSet) path(cfg *config, name string) string {
+	if rel, err := filepath.Rel(cfg.rootDir, cfg.workingDir()); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+		if p := filepath.Join(f.dir, rel, name); fileExists(p) {
+			return p
+		}
+	}
+
+	return filepath.Join(f.dir, name)
+}
+
+// load reads the run recorded for cfg again, so that edited fixtures are
+// picked up by the next lint.
+func (f *fixtureSet) load(cfg *config) ([]byte, fixtureRunInfo, error) {
+	var info fixtureRunInfo
+
+	stdout, err := ioutil.ReadFile(f.path(cfg, fixtureOutput))
+	if err != nil {
+		return nil, info, err
+	}
+
+	b, err := ioutil.ReadFile(f.path(cfg, fixtureRun))
+	if os.IsNotExist(err) {
+		return stdout, info, nil
+	}
+
+	if err != nil {
+		return nil, info, err
+	}
+
+	if err := json.Unmarshal(b, &info); err != nil {
+		return nil, info, fmt.Errorf("%s: %w", f.path(cfg, fixtureRun), err)
+	}
+
+	return stdout, info, nil
+}
+
+// replay returns the result of the recorded run standing for command, as run
+// would.
+func (h *langHandler) replay(ctx context.Context, cfg *config, command []string) (*GolangCILintResult, error) {
+	start := time.Now()
+
+	stdout, info, err := cfg.fixtures.load(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Delay != "" {
+		delay, err := time.ParseDuration(info.Delay)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", cfg.fixtures.path(cfg, fixtureRun), err)
+		}
+
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	h.setLastOutput(&runOutput{
+		command:  command,
+		dir:      cfg.workingDir(),
+		exitCode: info.ExitCode,
+		time:     start,
+		stdout:   stdout,
+		stderr:   []byte(info.Stderr),
+	})
+	h.recordStats(cfg, cfg.workingDir(), start, []byte(info.Stderr))
+
+	result := GolangCILintResult{buckets: newDiagnosticBuckets(cfg)}
+
+	output := stdout
+	if _, combined := cfg.backend.(combinedBackend); combined {
+		output = append(append([]byte{}, stdout...), info.Stderr...)
+	}
+
+	decodeErr := cfg.backend.decode(bytes.NewReader(output), &result)
+
+	h.logger.Event(levelDebug, "golangci-lint-langserver: replayed fixture", logFields{
+		"dir":      cfg.workingDir(),
+		"exitCode": info.ExitCode,
+		"duration": time.Since(start).String(),
+	})
+
+	return runResult(cfg, info.ExitCode, &result, decodeErr, info.Stderr)
+}
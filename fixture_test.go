@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// fixtureSession is a session replaying the fixtures of a temporary
+// workspace, connected to a client recording the published diagnostics.
+type fixtureSession struct {
+	h      *langHandler
+	server *jsonrpc2.Conn
+	client *jsonrpc2.Conn
+	dir    string
+	root   string
+
+	mu        sync.Mutex
+	published []PublishDiagnosticsParams
+	refreshes int
+}
+
+func newFixtureSession(tb testing.TB, output string, run *fixtureRunInfo) *fixtureSession {
+	tb.Helper()
+
+	root, err := ioutil.TempDir("", "golangci-lint-langserver")
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	ws, fixtures := filepath.Join(root, "ws"), filepath.Join(root, "fixtures")
+
+	write := func(name, content string) {
+		tb.Helper()
+
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			tb.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(name, []byte(content), 0o600); err != nil {
+			tb.Fatal(err)
+		}
+	}
+
+	write(filepath.Join(ws, "go.mod"), "module example.com/m\n\ngo 1.13\n")
+	write(filepath.Join(ws, "main.go"), "package main\n\nfunc main() {\n\tf()\n}\n")
+	write(filepath.Join(ws, "other.go"), "package main\n\nfunc f() error { return nil }\n")
+	write(filepath.Join(fixtures, fixtureOutput), output)
+
+	if run != nil {
+		b, err := json.Marshal(run)
+		if err != nil {
+			tb.Fatal(err)
+		}
+
+		write(filepath.Join(fixtures, fixtureRun), string(b))
+	}
+
+	shared := newSharedState()
+	shared.fixtures = &fixtureSet{dir: fixtures}
+
+	s := &fixtureSession{
+		h:    newLangHandler(newStdLogger(ioutil.Discard, 0, levelError, false), shared),
+		dir:  root,
+		root: ws,
+	}
+
+	serverSide, clientSide := net.Pipe()
+
+	s.server = jsonrpc2.NewConn(context.Background(), jsonrpc2.NewBufferedStream(serverSide, jsonrpc2.VSCodeObjectCodec{}), s.h.handler())
+	s.client = jsonrpc2.NewConn(context.Background(), jsonrpc2.NewBufferedStream(clientSide, jsonrpc2.VSCodeObjectCodec{}), jsonrpc2.HandlerWithError(s.handle))
+
+	params := InitializeParams{RootURI: string(pathToURI(ws))}
+	if err := s.client.Call(context.Background(), "initialize", params, nil); err != nil {
+		s.close()
+		tb.Fatal(err)
+	}
+
+	return s
+}
+
+func (s *fixtureSession) close() {
+	s.h.close()
+	s.client.Close()
+	s.server.Close()
+	os.RemoveAll(s.dir)
+}
+
+func (s *fixtureSession) handle(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if req.Method == "workspace/diagnostic/refresh" {
+		s.mu.Lock()
+		s.refreshes++
+		s.mu.Unlock()
+	}
+
+	if req.Method == "textDocument/publishDiagnostics" && req.Params != nil {
+		var params PublishDiagnosticsParams
+		if err := json.Unmarshal(*req.Params, &params); err != nil {
+			return nil, err
+		}
+
+		s.mu.Lock()
+		s.published = append(s.published, params)
+		s.mu.Unlock()
+	}
+
+	return nil, nil
+}
+
+// flush returns the diagnostics published so far and forgets them. As the
+// client handles messages in order, the notifications sent before the flush
+// request are all handled once it is answered.
+func (s *fixtureSession) flush(tb testing.TB) []PublishDiagnosticsParams {
+	tb.Helper()
+
+	if err := s.server.Call(context.Background(), "test/flush", nil, nil); err != nil {
+		tb.Fatal(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	published := s.published
+	s.published = nil
+
+	return published
+}
+
+func (s *fixtureSession) uri(name string) DocumentURI {
+	return pathToURI(filepath.Join(s.root, name))
+}
+
+// fixtureOutputOf returns the output of golangci-lint reporting issues.
+func fixtureOutputOf(issues ...string) string {
+	return `{"Issues":[` + strings.Join(issues, ",") + `],"Report":{"Linters":[]}}`
+}
+
+func fixtureIssue(linter, text, file string, line, column int) string {
+	return fmt.Sprintf(`{"FromLinter":%q,"Text":%q,"SourceLines":[],"Pos":{"Filename":%q,"Line":%d,"Column":%d},"LineRange":{"From":%d,"To":%d}}`,
+		linter, text, file, line, column, line, line)
+}
+
+func TestReplayFixture(t *testing.T) {
+	errcheck := fixtureIssue("errcheck", "Error return value is not checked", "main.go", 4, 2)
+
+	tests := []struct {
+		name   string
+		output string
+		run    *fixtureRunInfo
+		// want maps the published files to the messages of their
+		// diagnostics.
+		want map[string][]string
+	}{
+		{
+			name:   "issues",
+			output: fixtureOutputOf(errcheck, fixtureIssue("unparam", "f - result 0 (error) is always nil", "other.go", 3, 6)),
+			want: map[string][]string{
+				"main.go":  {"Error return value is not checked"},
+				"other.go": {"f - result 0 (error) is always nil"},
+			},
+		},
+		{
+			name:   "clean",
+			output: fixtureOutputOf(),
+			want:   map[string][]string{"main.go": {}, "other.go": {}},
+		},
+		{
+			name:   "issues exit code",
+			output: fixtureOutputOf(errcheck),
+			run:    &fixtureRunInfo{ExitCode: exitCodeIssuesFound},
+			want:   map[string][]string{"main.go": {"Error return value is not checked"}, "other.go": {}},
+		},
+		{
+			name:   "issues outside the workspace",
+			output: fixtureOutputOf(fixtureIssue("errcheck", "Error return value is not checked", "/elsewhere/main.go", 4, 2)),
+			want:   map[string][]string{"main.go": {}, "other.go": {}},
+		},
+		{
+			name:   "failure",
+			output: "",
+			run:    &fixtureRunInfo{ExitCode: 3, Stderr: "typechecking error"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newFixtureSession(t, tt.output, tt.run)
+			defer s.close()
+
+			s.h.lintAndPublish(s.root, []DocumentURI{s.uri("main.go"), s.uri("other.go")}, modeFull)
+
+			got := make(map[string][]string)
+
+			for _, params := range s.flush(t) {
+				name := filepath.Base(uriToPath(string(params.URI)))
+				messages := []string{}
+
+				for _, d := range params.Diagnostics {
+					messages = append(messages, d.Message)
+				}
+
+				got[name] = messages
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("published %v, want %v", got, tt.want)
+			}
+
+			for name, want := range tt.want {
+				if strings.Join(got[name], "\n") != strings.Join(want, "\n") || got[name] == nil {
+					t.Errorf("%s: published %q, want %q", name, got[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestReplayFixtureRange(t *testing.T) {
+	s := newFixtureSession(t, fixtureOutputOf(fixtureIssue("errcheck", "Error return value is not checked", "main.go", 4, 2)), nil)
+	defer s.close()
+
+	s.h.lintAndPublish(s.root, []DocumentURI{s.uri("main.go")}, modeFull)
+
+	published := s.flush(t)
+	if len(published) != 1 || len(published[0].Diagnostics) != 1 {
+		t.Fatalf("published %v, want one diagnostic", published)
+	}
+
+	d := published[0].Diagnostics[0]
+	if d.Range.Start.Line != 3 || d.Range.Start.Character != 1 {
+		t.Errorf("range starts at %d:%d, want 3:1", d.Range.Start.Line, d.Range.Start.Character)
+	}
+
+	if d.Source == nil || *d.Source != "errcheck" {
+		t.Errorf("source = %v, want errcheck", d.Source)
+	}
+}
+
+func BenchmarkReplayFixture(b *testing.B) {
+	issues := make([]string, 0, 10000)
+	for i := 0; i < cap(issues); i++ {
+		issues = append(issues, fixtureIssue("errcheck", fmt.Sprintf("issue %d", i), "main.go", 4, 2))
+	}
+
+	s := newFixtureSession(b, fixtureOutputOf(issues...), nil)
+	defer s.close()
+
+	uris := []DocumentURI{s.uri("main.go")}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		s.h.cache.clearUnder([]string{s.root})
+		s.h.lintAndPublish(s.root, uris, modeFull)
+		s.flush(b)
+	}
+}
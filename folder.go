@@ -0,0 +1,127 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// FolderOptions override the options for the files in a workspace folder.
+type FolderOptions struct {
+	Command   []string          `json:"command,omitempty"`
+	Container *ContainerOptions `json:"container,omitempty"`
+	SSH       *SSHOptions       `json:"ssh,omitempty"`
+	Severity  map[string]string `json:"severity,omitempty"`
+
+	PackagesDriver string `json:"packagesDriver,omitempty"`
+}
+
+// sessionDirs returns the directories whose files the session of c lints:
+// the root, the workspace folders and the other roots.
+func (c *config) sessionDirs() []string {
+	dirs := append([]string{}, c.roots...)
+	if c.rootDir != "" {
+		dirs = append(dirs, c.rootDir)
+	}
+
+	for dir := range c.folders {
+		dirs = append(dirs, dir)
+	}
+
+	return dirs
+}
+
+// folderDirs returns the directories of the workspace folders and of the
+// folders having overrides, relative ones being resolved against rootDir.
+func folderDirs(rootDir string, folders []WorkspaceFolder, overrides map[string]FolderOptions) map[string]*FolderOptions {
+	dirs := make(map[string]*FolderOptions)
+
+	for _, folder := range folders {
+		dirs[filepath.Clean(uriToPath(folder.URI))] = nil
+	}
+
+	for key, opts := range overrides {
+		opts := opts
+
+		dir := key
+		if strings.HasPrefix(key, "file://") {
+			dir = uriToPath(key)
+		} else if !filepath.IsAbs(dir) {
+			dir = filepath.Join(rootDir, dir)
+		}
+
+		dirs[filepath.Clean(dir)] = &opts
+	}
+
+	return dirs
+}
+
+// forFile returns the config to lint the file at path with: the one of the
+// innermost workspace folder containing it, running in the working directory
+// found for the file.
+func (c *config) forFile(path string) *config {
+	return c.forFolder(path).forModule(path).forModuleless(path)
+}
+
+func (c *config) forFolder(path string) *config {
+	var (
+		dir  string
+		opts *FolderOptions
+	)
+
+	target := normalizePath(path)
+
+	for d, o := range c.folders {
+		n := normalizePath(d)
+		if (target == n || strings.HasPrefix(target, n+string(filepath.Separator))) && len(d) > len(dir) {
+			dir, opts = d, o
+		}
+	}
+
+	if dir == "" || dir == c.rootDir && opts == nil {
+		return c
+	}
+
+	fc := *c
+	fc.sessionRoot = c.workspaceRoot()
+	fc.rootDir = dir
+	fc.rootURI = string(pathToURI(dir))
+	fc.dirSource = dirFromFolder
+
+	if c.executor != nil {
+		fc.executor = c.executor.forDir(dir)
+	}
+
+	if opts == nil {
+		return &fc
+	}
+
+	if len(opts.Command) > 0 {
+		fc.command = opts.Command
+	}
+
+	if opts.PackagesDriver != "" {
+		fc.packagesDriver = opts.PackagesDriver
+	}
+
+	switch {
+	case opts.Container != nil:
+		fc.executor = newContainerExecutor(*opts.Container, dir)
+	case opts.SSH != nil:
+		fc.executor = newSSHExecutor(*opts.SSH, dir)
+	}
+
+	if len(opts.Severity) > 0 {
+		severities := make(map[string]DiagnosticSeverity, len(c.severities)+len(opts.Severity))
+		for k, v := range c.severities {
+			severities[k] = v
+		}
+
+		for k, v := range parseSeverities(opts.Severity) {
+			severities[k] = v
+		}
+
+		fc.severities = severities
+	}
+
+	return &fc
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// checkIDPattern matches check IDs such as SA1019 or ST1003 that linters put
+// in front of their messages.
+var checkIDPattern = regexp.MustCompile(`^([A-Z]+[0-9]+): `)
+
+// messageData is passed to the messageTemplate.
+type messageData struct {
+	// Text is the message as reported by golangci-lint.
+	Text string
+	// Message is Text without the leading check ID.
+	Message string
+	// CheckID is the check ID found at the start of Text, if any.
+	CheckID string
+	Linter  string
+}
+
+func newMessageData(issue *Issue) messageData {
+	data := messageData{Text: issue.Text, Message: issue.Text, Linter: issue.FromLinter}
+
+	if m := checkIDPattern.FindStringSubmatch(issue.Text); m != nil {
+		data.CheckID = m[1]
+		data.Message = issue.Text[len(m[0]):]
+	}
+
+	return data
+}
+
+func (h *langHandler) parseMessageTemplate(text string) *template.Template {
+	if text == "" {
+		return nil
+	}
+
+	tmpl, err := template.New("message").Parse(text)
+	if err != nil {
+		h.logger.Errorf("golangci-lint-langserver: messageTemplate: %s", err)
+
+		return nil
+	}
+
+	return tmpl
+}
+
+// message renders the diagnostic message of issue.
+func (c *config) message(issue *Issue) string {
+	if c.messageTemplate == nil {
+		return issue.Text
+	}
+
+	var b strings.Builder
+	if err := c.messageTemplate.Execute(&b, newMessageData(issue)); err != nil {
+		return issue.Text
+	}
+
+	return b.String()
+}
+
+// checkID returns the check ID of issue to be used as the diagnostic code.
+func checkID(issue *Issue) *string {
+	m := checkIDPattern.FindStringSubmatch(issue.Text)
+	if m == nil {
+		return nil
+	}
+
+	return &m[1]
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"strings"
+)
+
+// maxPreviewLines bounds the lines of a formatting hunk shown in messages.
+const maxPreviewLines = 12
+
+// formatters are the linters reporting files that are not formatted.
+var formatters = map[string]bool{
+	"gci":       true,
+	"gofmt":     true,
+	"gofumpt":   true,
+	"goimports": true,
+	"golines":   true,
+}
+
+// Replacement is the fix golangci-lint proposes for an issue: whole lines, or
+// a part of the line of the issue.
+type Replacement struct {
+	NeedOnlyDelete bool     `json:"NeedOnlyDelete,omitempty"`
+	NewLines       []string `json:"NewLines,omitempty"`
+	Inline         *struct {
+		StartCol  int    `json:"StartCol"`
+		Length    int    `json:"Length"`
+		NewString string `json:"NewString"`
+	} `json:"Inline,omitempty"`
+}
+
+// fileLines reads the lines of files once per set of issues.
+type fileLines map[string][]string
+
+func (f fileLines) get(path string) ([]string, bool) {
+	lines, ok := f[path]
+	if !ok {
+		b, err := ioutil.ReadFile(path)
+		if err == nil {
+			lines = strings.Split(string(b), "\n")
+		}
+
+		f[path] = lines
+	}
+
+	return lines, lines != nil
+}
+
+// formatHunk returns the edit fixing the formatting issue of a formatter in
+// path, with a preview of the hunk it changes. The hunk comes from the
+// replacement reported by golangci-lint or, for gofmt, from formatting the
+// file.
+func formatHunk(issue *Issue, path string, files fileLines) (*TextEdit, string, bool) {
+	if !formatters[issue.FromLinter] {
+		return nil, "", false
+	}
+
+	lines, ok := files.get(path)
+	if !ok {
+		return nil, "", false
+	}
+
+	from, to := issue.LineRange.From, issue.LineRange.To
+	if to < from {
+		to = from
+	}
+
+	if r := issue.Replacement; r != nil && from > 0 && to <= len(lines) {
+		var newLines []string
+		if !r.NeedOnlyDelete {
+			newLines = r.NewLines
+		}
+
+		return hunkEdit(lines, from-1, to, newLines)
+	}
+
+	if issue.FromLinter != "gofmt" {
+		return nil, "", false
+	}
+
+	formatted, err := format.Source([]byte(strings.Join(lines, "\n")))
+	if err != nil {
+		return nil, "", false
+	}
+
+	newLines := strings.Split(string(formatted), "\n")
+
+	// the smallest hunk covering every change: the lines before the first
+	// and after the last difference are kept
+	start := 0
+	for start < len(lines) && start < len(newLines) && lines[start] == newLines[start] {
+		start++
+	}
+
+	if start == len(lines) && start == len(newLines) {
+		return nil, "", false
+	}
+
+	end, newEnd := len(lines), len(newLines)
+	for end > start && newEnd > start && lines[end-1] == newLines[newEnd-1] {
+		end--
+		newEnd--
+	}
+
+	return hunkEdit(lines, start, end, newLines[start:newEnd])
+}
+
+// hunkEdit returns the edit replacing lines[start:end] by newLines, with its
+// preview as a diff.
+func hunkEdit(lines []string, start, end int, newLines []string) (*TextEdit, string, bool) {
+	edit := &TextEdit{Range: Range{Start: Position{Line: start}, End: Position{Line: end}}}
+
+	if len(newLines) > 0 {
+		edit.NewText = strings.Join(newLines, "\n") + "\n"
+	}
+
+	if end >= len(lines) {
+		// the last line has no newline to replace
+		edit.Range.End = Position{Line: len(lines) - 1, Character: utf16Offset(lines[len(lines)-1], len(lines[len(lines)-1]))}
+		edit.NewText = strings.TrimSuffix(edit.NewText, "\n")
+	}
+
+	var preview []string
+
+	for _, l := range lines[start:end] {
+		preview = append(preview, "-"+l)
+	}
+
+	for _, l := range newLines {
+		preview = append(preview, "+"+l)
+	}
+
+	if len(preview) > maxPreviewLines {
+		preview = append(preview[:maxPreviewLines], fmt.Sprintf("… %d more lines", len(preview)-maxPreviewLines))
+	}
+
+	return edit, strings.Join(preview, "\n"), true
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var generatedPattern = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// isGenerated reports whether the Go source has the standard header of
+// generated files, which must appear before the package clause.
+func isGenerated(src string) bool {
+	sc := bufio.NewScanner(strings.NewReader(src))
+
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+
+		if generatedPattern.MatchString(line) {
+			return true
+		}
+
+		if strings.HasPrefix(line, "package ") {
+			return false
+		}
+	}
+
+	return false
+}
+
+// isGeneratedFile is like isGenerated, using the text of the open document or
+// else the file on disk.
+func (h *langHandler) isGeneratedFile(uri DocumentURI) bool {
+	h.docsMu.Lock()
+	doc, ok := h.docs[uri]
+	h.docsMu.Unlock()
+
+	if ok {
+		return isGenerated(doc.text)
+	}
+
+	f, err := os.Open(uriToPath(string(uri)))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	var b strings.Builder
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		b.WriteString(sc.Text())
+		b.WriteByte('\n')
+
+		if strings.HasPrefix(sc.Text(), "package ") {
+			break
+		}
+	}
+
+	return isGenerated(b.String())
+}
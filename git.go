@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// gitDirty only lints files with uncommitted changes.
+	gitDirty = "dirty"
+	// gitChangedLines also only publishes the diagnostics on changed lines.
+	gitChangedLines = "changedLines"
+)
+
+// hunkPattern matches the new file range of a unified diff hunk header.
+var hunkPattern = regexp.MustCompile(`(?m)^@@ -[0-9,]+ \+([0-9]+)(?:,([0-9]+))? @@`)
+
+// gitChanges are the uncommitted changes of a git repository.
+type gitChanges struct {
+	root string
+	// files maps the normalized paths of changed files to whether they are
+	// untracked.
+	files map[string]bool
+}
+
+// gitStatus returns the uncommitted changes of the repository containing dir.
+func gitStatus(dir string) (*gitChanges, error) {
+	out, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &gitChanges{root: strings.TrimSpace(string(out)), files: make(map[string]bool)}
+
+	out, err = git(dir, "status", "--porcelain", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, err
+	}
+
+	entries := strings.Split(string(out), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+
+		status, name := entry[:2], entry[3:]
+		changes.files[normalizePath(filepath.Join(changes.root, filepath.FromSlash(name)))] = status == "??"
+
+		if status[0] == 'R' || status[0] == 'C' {
+			// the original path follows renames and copies
+			i++
+		}
+	}
+
+	return changes, nil
+}
+
+// dirty reports whether filename has uncommitted changes.
+func (g *gitChanges) dirty(filename string) bool {
+	_, ok := g.files[normalizePath(filename)]
+
+	return ok
+}
+
+// changedLines returns the ranges of 1-based lines of filename changed since
+// HEAD, or all set when the whole file is new.
+func (g *gitChanges) changedLines(filename string) (ranges [][2]int, all bool) {
+	untracked, ok := g.files[normalizePath(filename)]
+	if !ok {
+		return nil, false
+	}
+
+	if untracked {
+		return nil, true
+	}
+
+	out, err := git(g.root, "diff", "-U0", "HEAD", "--", filename)
+	if err != nil {
+		// without HEAD every file is new
+		return nil, true
+	}
+
+	for _, m := range hunkPattern.FindAllSubmatch(out, -1) {
+		start, _ := strconv.Atoi(string(m[1]))
+		count := 1
+
+		if len(m[2]) > 0 {
+			count, _ = strconv.Atoi(string(m[2]))
+		}
+
+		if count > 0 {
+			ranges = append(ranges, [2]int{start, start + count - 1})
+		}
+	}
+
+	return ranges, false
+}
+
+// filterChangedLines returns the diagnostics of filename that start on a line
+// changed since HEAD.
+func (g *gitChanges) filterChangedLines(filename string, diagnostics []Diagnostic) []Diagnostic {
+	ranges, all := g.changedLines(filename)
+	if all {
+		return diagnostics
+	}
+
+	filtered := make([]Diagnostic, 0, len(diagnostics))
+
+	for _, d := range diagnostics {
+		line := d.Range.Start.Line + 1
+
+		for _, r := range ranges {
+			if line >= r[0] && line <= r[1] {
+				filtered = append(filtered, d)
+
+				break
+			}
+		}
+	}
+
+	return filtered
+}
+
+func git(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
+
+// gitChanges returns the uncommitted changes to restrict linting to, or nil
+// when linting is not restricted.
+func (h *langHandler) gitChanges(cfg *config) *gitChanges {
+	if cfg.gitMode == "" {
+		return nil
+	}
+
+	changes, err := gitStatus(cfg.workingDir())
+	if err != nil {
+		h.logger.Errorf("golangci-lint-langserver: %s", err)
+
+		return nil
+	}
+
+	return changes
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"path"
+	"strings"
+)
+
+// matchGlob reports whether the slash-separated name matches pattern, where
+// "**" matches any number of path segments and other segments follow
+// path.Match.
+func matchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			if len(rest) == 0 {
+				return true
+			}
+
+			for i := range name {
+				if matchSegments(rest, name[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
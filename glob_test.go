@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "main.go", name: "main.go", want: true},
+		{pattern: "*.go", name: "main.go", want: true},
+		{pattern: "*.go", name: "cmd/main.go", want: false},
+		{pattern: "**/*.go", name: "main.go", want: true},
+		{pattern: "**/*.go", name: "cmd/app/main.go", want: true},
+		{pattern: "**/*_test.go", name: "cmd/main.go", want: false},
+		{pattern: "vendor/**", name: "vendor/a/b.go", want: true},
+		{pattern: "vendor/**", name: "vendor", want: true},
+		{pattern: "vendor/**", name: "internal/vendor/a.go", want: false},
+		{pattern: "**/testdata/**", name: "pkg/testdata/x/y.go", want: true},
+		{pattern: "internal/*/gen.go", name: "internal/a/gen.go", want: true},
+		{pattern: "internal/*/gen.go", name: "internal/a/b/gen.go", want: false},
+		{pattern: "gen_?.go", name: "gen_a.go", want: true},
+		{pattern: "[", name: "[", want: false},
+		{pattern: "a/b", name: "a", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			if got := matchGlob(tt.pattern, tt.name); got != tt.want {
+				t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,4 +2,8 @@ module github.com/nametake/golangci-lint-langserver
 
 go 1.13
 
-require github.com/sourcegraph/jsonrpc2 v0.0.0-20191222043438-96c4efab7ee2
+require (
+	github.com/gorilla/websocket v1.4.1
+	github.com/sourcegraph/jsonrpc2 v0.0.0-20191222043438-96c4efab7ee2
+	gopkg.in/yaml.v3 v3.0.1
+)
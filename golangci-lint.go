@@ -1,28 +1,136 @@
 package main
 
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
 //nolint:unused,deadcode
 type GolangCILintResult struct {
-	Issues []struct {
-		FromLinter  string      `json:"FromLinter"`
-		Text        string      `json:"Text"`
-		SourceLines []string    `json:"SourceLines"`
-		Replacement interface{} `json:"Replacement"`
-		Pos         struct {
-			Filename string `json:"Filename"`
-			Offset   int    `json:"Offset"`
-			Line     int    `json:"Line"`
-			Column   int    `json:"Column"`
-		} `json:"Pos"`
-		LineRange struct {
-			From int `json:"From"`
-			To   int `json:"To"`
-		} `json:"LineRange,omitempty"`
-	} `json:"Issues"`
+	Issues []Issue `json:"Issues"`
 	Report struct {
 		Linters []struct {
 			Name             string `json:"Name"`
 			Enabled          bool   `json:"Enabled"`
 			EnabledByDefault bool   `json:"EnabledByDefault,omitempty"`
 		} `json:"Linters"`
+		Warnings []struct {
+			Tag  string `json:"Tag,omitempty"`
+			Text string `json:"Text"`
+		} `json:"Warnings,omitempty"`
+		Error string `json:"Error,omitempty"`
 	} `json:"Report"`
+
+	// buckets, when set, converts the issues as they are decoded.
+	buckets *diagnosticBuckets
+}
+
+// addIssue appends issue to the result, converting it to a diagnostic right
+// away when the result has buckets.
+func (r *GolangCILintResult) addIssue(issue Issue) {
+	r.Issues = append(r.Issues, issue)
+
+	if r.buckets != nil {
+		r.buckets.add(issue)
+	}
+}
+
+type Issue struct {
+	FromLinter  string       `json:"FromLinter"`
+	Text        string       `json:"Text"`
+	SourceLines []string     `json:"SourceLines"`
+	Replacement *Replacement `json:"Replacement"`
+	Severity    string       `json:"Severity,omitempty"`
+	Pos         struct {
+		Filename string `json:"Filename"`
+		Offset   int    `json:"Offset"`
+		Line     int    `json:"Line"`
+		Column   int    `json:"Column"`
+	} `json:"Pos"`
+	LineRange struct {
+		From int `json:"From"`
+		To   int `json:"To"`
+	} `json:"LineRange,omitempty"`
+	// ExpectNoLint and ExpectedNoLintLinter are set on the nolintlint issues
+	// of unused directives.
+	ExpectNoLint         bool   `json:"ExpectNoLint,omitempty"`
+	ExpectedNoLintLinter string `json:"ExpectedNoLintLinter,omitempty"`
+	// Variants are the build variants reporting the issue, when not all
+	// of them do.
+	Variants []string `json:"Variants,omitempty"`
+}
+
+// decodeResult decodes the JSON output of golangci-lint from r. Issues are
+// decoded one at a time, so that the output is never buffered as a whole.
+func decodeResult(r io.Reader, result *GolangCILintResult) error {
+	dec := json.NewDecoder(r)
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch tok {
+		case "Issues":
+			if err := decodeIssues(dec, result); err != nil {
+				return err
+			}
+		case "Report":
+			if err := dec.Decode(&result.Report); err != nil {
+				return err
+			}
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return err
+			}
+		}
+	}
+
+	return expectDelim(dec, '}')
+}
+
+func decodeIssues(dec *json.Decoder, result *GolangCILintResult) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if tok == nil {
+		return nil
+	}
+
+	if tok != json.Delim('[') {
+		return fmt.Errorf("unexpected %v in Issues", tok)
+	}
+
+	for dec.More() {
+		var issue Issue
+		if err := dec.Decode(&issue); err != nil {
+			return err
+		}
+
+		result.addIssue(issue)
+	}
+
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if tok != delim {
+		return fmt.Errorf("unexpected %v, expected %v", tok, delim)
+	}
+
+	return nil
 }
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		texts   []string
+		report  string
+		wantErr bool
+	}{
+		{
+			name:   "issues",
+			output: `{"Issues":[{"FromLinter":"errcheck","Text":"a"},{"FromLinter":"gosec","Text":"b"}],"Report":{"Linters":[]}}`,
+			texts:  []string{"a", "b"},
+		},
+		{
+			name:   "null issues",
+			output: `{"Issues":null,"Report":{"Error":"can't load config"}}`,
+			report: "can't load config",
+		},
+		{
+			name:   "report first",
+			output: `{"Report":{"Warnings":[{"Text":"w"}]},"Issues":[{"Text":"a"}]}`,
+			texts:  []string{"a"},
+		},
+		{
+			name:   "unknown keys",
+			output: `{"Version":{"x":[1,2]},"Issues":[{"Text":"a","Unknown":true}]}`,
+			texts:  []string{"a"},
+		},
+		{
+			name:   "trailing output",
+			output: "{\"Issues\":[{\"Text\":\"a\"}]}\nlevel=info msg=\"done\"\n",
+			texts:  []string{"a"},
+		},
+		{
+			name:    "empty",
+			wantErr: true,
+		},
+		{
+			name:    "not an object",
+			output:  `[]`,
+			wantErr: true,
+		},
+		{
+			name:    "issues not an array",
+			output:  `{"Issues":{}}`,
+			wantErr: true,
+		},
+		{
+			name:    "truncated",
+			output:  `{"Issues":[{"Text":"a"},{"Te`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result GolangCILintResult
+
+			err := decodeResult(strings.NewReader(tt.output), &result)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeResult() error = %v, want error %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			texts := make([]string, 0, len(result.Issues))
+			for _, issue := range result.Issues {
+				texts = append(texts, issue.Text)
+			}
+
+			if strings.Join(texts, ",") != strings.Join(tt.texts, ",") {
+				t.Errorf("issues %q, want %q", texts, tt.texts)
+			}
+
+			if result.Report.Error != tt.report {
+				t.Errorf("report error %q, want %q", result.Report.Error, tt.report)
+			}
+		})
+	}
+}
+
+func TestDecodeResultBuckets(t *testing.T) {
+	c := &config{}
+
+	result := GolangCILintResult{buckets: newDiagnosticBuckets(c)}
+	output := `{"Issues":[` +
+		`{"FromLinter":"errcheck","Text":"a","Pos":{"Filename":"/ws/a.go","Line":1,"Column":1}},` +
+		`{"FromLinter":"errcheck","Text":"b","Pos":{"Filename":"/ws/b.go","Line":2,"Column":1}},` +
+		`{"FromLinter":"gosec","Text":"c","Pos":{"Filename":"/ws/a.go","Line":3,"Column":1}}]}`
+
+	if err := decodeResult(strings.NewReader(output), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	files := c.diagnosticsByFile(&result)
+	if len(files[normalizePath("/ws/a.go")]) != 2 || len(files[normalizePath("/ws/b.go")]) != 1 {
+		t.Errorf("diagnosticsByFile() = %v", files)
+	}
+
+	// the diagnostics are handed over once, then converted again
+	if again := c.diagnosticsByFile(&result); len(again) != len(files) {
+		t.Errorf("diagnosticsByFile() again = %v", again)
+	}
+}
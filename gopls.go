@@ -0,0 +1,38 @@
+package main
+
+const (
+	goplsDrop = "drop"
+	goplsTag  = "tag"
+)
+
+// defaultGoplsLinters are the linters whose findings gopls reports as well.
+var defaultGoplsLinters = []string{"govet", "staticcheck"}
+
+// GoplsOptions configures how findings that gopls reports as well are
+// handled when both servers run.
+type GoplsOptions struct {
+	// Dedup is "drop" to not publish them, or "tag" to mark them in the
+	// diagnostic data so that clients can merge them.
+	Dedup   string   `json:"dedup"`
+	Linters []string `json:"linters,omitempty"`
+}
+
+// goplsDuplicate reports whether gopls reports the findings of linter too.
+func (c *config) goplsDuplicate(linter string) bool {
+	if c.gopls == nil {
+		return false
+	}
+
+	linters := c.gopls.Linters
+	if len(linters) == 0 {
+		linters = defaultGoplsLinters
+	}
+
+	for _, l := range linters {
+		if l == linter {
+			return true
+		}
+	}
+
+	return false
+}
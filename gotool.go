@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// toolDirectivePattern matches golangci-lint in the tool directives of
+	// go.mod, added by go get -tool since Go 1.24.
+	toolDirectivePattern = regexp.MustCompile(`(?m)^\s*(?:tool\s+)?(github\.com/golangci/golangci-lint(?:/v2)?/cmd/golangci-lint)\s*$`)
+	// toolRequirePattern matches a requirement of golangci-lint, as made by
+	// the tools.go convention.
+	toolRequirePattern = regexp.MustCompile(`(?m)^\s*(?:require\s+)?github\.com/golangci/golangci-lint(/v2)?\s+v`)
+	// toolReplacePattern matches a replacement of golangci-lint, which would
+	// build the code of the repository instead.
+	toolReplacePattern = regexp.MustCompile(`(?m)^\s*(?:replace\s+)?github\.com/golangci/golangci-lint(?:/v2)?(?:\s+v\S+)?\s+=>`)
+
+	errUnknownResolution = errors.New("unknown commandResolution, expected goTool")
+	errToolReplaced      = errors.New("go.mod replaces golangci-lint")
+)
+
+const resolutionGoTool = "goTool"
+
+// golangciLintPackage returns the main package of golangci-lint version.
+func golangciLintPackage(version string) string {
+	if strings.HasPrefix(strings.TrimPrefix(version, "v"), "2.") {
+		return "github.com/golangci/golangci-lint/v2/cmd/golangci-lint"
+	}
+
+	return golangciLintModule
+}
+
+// moduleToolCommand returns the command running the golangci-lint pinned by
+// the module of dir, either as a tool or as a requirement, or nil. It fails
+// when go.mod replaces golangci-lint.
+func moduleToolCommand(dir string) ([]string, error) {
+	modDir, ok := findModule(dir)
+	if !ok {
+		return nil, nil
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(modDir, "go.mod"))
+	if err != nil {
+		return nil, nil
+	}
+
+	return toolCommand(b)
+}
+
+// toolCommand returns the command running the golangci-lint pinned by the
+// go.mod content b, or nil.
+func toolCommand(b []byte) ([]string, error) {
+	if toolReplacePattern.Match(b) {
+		return nil, errToolReplaced
+	}
+
+	if toolDirectivePattern.Match(b) {
+		return []string{"go", "tool", "golangci-lint"}, nil
+	}
+
+	if m := toolRequirePattern.FindSubmatch(b); m != nil {
+		return []string{"go", "run", "github.com/golangci/golangci-lint" + string(m[1]) + "/cmd/golangci-lint"}, nil
+	}
+
+	return nil, nil
+}
+
+// useModuleTool runs the golangci-lint pinned by the module of the workspace
+// instead of the one on PATH when resolution is goTool, unless the command
+// names another binary.
+func (c *config) useModuleTool(resolution string) error {
+	switch resolution {
+	case "":
+		return nil
+	case resolutionGoTool:
+	default:
+		return fmt.Errorf("%w: %q", errUnknownResolution, resolution)
+	}
+
+	if c.backendName != defaultBackend || c.executor != nil || c.install != nil || c.report != nil ||
+		c.fixtures != nil || len(c.command) == 0 || c.command[0] != defaultCommand[0] {
+		return nil
+	}
+
+	tool, err := moduleToolCommand(c.rootDir)
+	if tool != nil {
+		c.command = append(tool, c.command[1:]...)
+	}
+
+	return err
+}
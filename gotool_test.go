@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestToolCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		mod  string
+		want []string
+		err  error
+	}{
+		{
+			name: "none",
+			mod:  "module example.com/m\n\ngo 1.22\n",
+		},
+		{
+			name: "tool directive",
+			mod:  "module example.com/m\n\ntool github.com/golangci/golangci-lint/v2/cmd/golangci-lint\n",
+			want: []string{"go", "tool", "golangci-lint"},
+		},
+		{
+			name: "tool block",
+			mod:  "module example.com/m\n\ntool (\n\tgithub.com/golangci/golangci-lint/cmd/golangci-lint\n)\n",
+			want: []string{"go", "tool", "golangci-lint"},
+		},
+		{
+			name: "requirement",
+			mod:  "module example.com/m\n\nrequire github.com/golangci/golangci-lint v1.55.2\n",
+			want: []string{"go", "run", "github.com/golangci/golangci-lint/cmd/golangci-lint"},
+		},
+		{
+			name: "v2 requirement",
+			mod:  "module example.com/m\n\nrequire (\n\tgithub.com/golangci/golangci-lint/v2 v2.1.0 // indirect\n)\n",
+			want: []string{"go", "run", "github.com/golangci/golangci-lint/v2/cmd/golangci-lint"},
+		},
+		{
+			name: "replace directive",
+			mod:  "module example.com/m\n\nrequire github.com/golangci/golangci-lint v1.55.2\n\nreplace github.com/golangci/golangci-lint => ./evil\n",
+			err:  errToolReplaced,
+		},
+		{
+			name: "versioned replace block",
+			mod:  "module example.com/m\n\ntool github.com/golangci/golangci-lint/v2/cmd/golangci-lint\n\nreplace (\n\tgithub.com/golangci/golangci-lint/v2 v2.1.0 => example.com/fork v1.0.0\n)\n",
+			err:  errToolReplaced,
+		},
+		{
+			name: "other replacement",
+			mod:  "module example.com/m\n\nrequire github.com/golangci/golangci-lint v1.55.2\n\nreplace example.com/dep => ../dep\n",
+			want: []string{"go", "run", "github.com/golangci/golangci-lint/cmd/golangci-lint"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toolCommand([]byte(tt.mod))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("toolCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
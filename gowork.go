@@ -0,0 +1,74 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// workModules returns the directories of the modules used by the go.work
+// file in rootDir. golangci-lint does not handle workspaces well, so each
+// module is linted on its own.
+func workModules(rootDir string) []string {
+	b, err := ioutil.ReadFile(filepath.Join(rootDir, "go.work"))
+	if err != nil {
+		return nil
+	}
+
+	var (
+		dirs  []string
+		block bool
+	)
+
+	for _, line := range strings.Split(string(b), "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+
+		line = strings.TrimSpace(line)
+
+		switch {
+		case block && line == ")":
+			block = false
+
+			continue
+		case block:
+		case line == "use(" || strings.HasPrefix(line, "use ") && strings.TrimSpace(line[3:]) == "(":
+			block = true
+
+			continue
+		case strings.HasPrefix(line, "use "):
+			line = strings.TrimSpace(line[3:])
+		default:
+			continue
+		}
+
+		if line == "" {
+			continue
+		}
+
+		if unquoted, err := strconv.Unquote(line); err == nil {
+			line = unquoted
+		}
+
+		dir := filepath.FromSlash(line)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(rootDir, dir)
+		}
+
+		dirs = append(dirs, filepath.Clean(dir))
+	}
+
+	return dirs
+}
+
+// addWorkModules adds the modules of the go.work file in rootDir to folders,
+// so that files are linted in their module.
+func addWorkModules(rootDir string, folders map[string]*FolderOptions) {
+	for _, dir := range workModules(rootDir) {
+		if _, ok := folders[dir]; !ok {
+			folders[dir] = nil
+		}
+	}
+}
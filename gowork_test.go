@@ -0,0 +1,71 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWorkModules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golangci-lint-langserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name string
+		work string
+		want []string
+	}{
+		{name: "no go.work"},
+		{
+			name: "single use",
+			work: "go 1.22\n\nuse ./a\n",
+			want: []string{filepath.Join(dir, "a")},
+		},
+		{
+			name: "block",
+			work: "go 1.22\n\nuse (\n\t./a\n\t// ./old\n\t./b/c // nested\n\n)\n",
+			want: []string{filepath.Join(dir, "a"), filepath.Join(dir, "b", "c")},
+		},
+		{
+			name: "block without space",
+			work: "use(\n\t.\n)\n",
+			want: []string{dir},
+		},
+		{
+			name: "quoted",
+			work: "use \"./with space\"\n",
+			want: []string{filepath.Join(dir, "with space")},
+		},
+		{
+			name: "absolute",
+			work: "use " + filepath.ToSlash(filepath.Join(dir, "abs")) + "\n",
+			want: []string{filepath.Join(dir, "abs")},
+		},
+		{
+			name: "other directives",
+			work: "go 1.22\n\ntoolchain go1.22.1\n\nreplace example.com/a => ./a\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := filepath.Join(dir, "go.work")
+			os.Remove(name)
+
+			if tt.work != "" {
+				if err := ioutil.WriteFile(name, []byte(tt.work), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := workModules(dir); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("workModules() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
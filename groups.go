@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var errFoundGoFile = errors.New("found a Go file")
+
+// lintGroup is a part of the workspace linted on its own.
+type lintGroup struct {
+	cfg     *config
+	pattern string
+}
+
+// workspaceGroups splits the workspace of cfg into the modules of its go.work
+// file, or else into the package at its root and the trees of the directories
+// below, so that a package failing to load spoils only its group.
+func workspaceGroups(cfg *config) []lintGroup {
+	var groups []lintGroup
+
+	if modules := workModules(cfg.rootDir); len(modules) > 0 {
+		for _, dir := range modules {
+			groups = append(groups, lintGroup{cfg: cfg.forFolder(filepath.Join(dir, "go.mod")), pattern: "./..."})
+		}
+
+		return groups
+	}
+
+	entries, err := ioutil.ReadDir(cfg.workingDir())
+	if err != nil {
+		return nil
+	}
+
+	rootPackage := false
+
+	for _, fi := range entries {
+		name := fi.Name()
+
+		switch {
+		case !fi.IsDir():
+			rootPackage = rootPackage || strings.HasSuffix(name, ".go")
+		case strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_"),
+			name == "vendor" || name == "testdata",
+			fileExists(filepath.Join(cfg.workingDir(), name, "go.mod")):
+			// go skips them in ./... too
+		case hasGoFiles(filepath.Join(cfg.workingDir(), name)):
+			groups = append(groups, lintGroup{cfg: cfg, pattern: "./" + name + "/..."})
+		}
+	}
+
+	if rootPackage {
+		groups = append([]lintGroup{{cfg: cfg, pattern: "."}}, groups...)
+	}
+
+	return groups
+}
+
+// hasGoFiles reports whether there is a Go file in the tree of dir.
+func hasGoFiles(dir string) bool {
+	err := filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if !fi.IsDir() && strings.HasSuffix(path, ".go") {
+			return errFoundGoFile
+		}
+
+		return nil
+	})
+
+	return errors.Is(err, errFoundGoFile)
+}
+
+// lintGroups lints the groups of the workspace one by one after the run of
+// the whole workspace failed with cause. The issues of the groups that
+// succeeded are returned together, with paths resolved, or cause if every
+// group failed.
+func (h *langHandler) lintGroups(cfg *config, cause error) (*GolangCILintResult, error) {
+	groups := workspaceGroups(cfg)
+	if len(groups) < 2 {
+		return nil, cause
+	}
+
+	h.logger.Printf("golangci-lint-langserver: linting the workspace in %d groups: %s", len(groups), cause)
+
+	var (
+		result GolangCILintResult
+		failed []string
+	)
+
+	for _, g := range groups {
+		r, err := h.timedRun(h.ctx, g.cfg, niceLintArgs(g.pattern))
+		if h.ctx.Err() != nil {
+			return nil, h.ctx.Err()
+		}
+
+		name := g.pattern
+		if rel, relErr := filepath.Rel(cfg.workingDir(), g.cfg.workingDir()); relErr == nil && rel != "." {
+			name = filepath.ToSlash(rel) + "/" + strings.TrimPrefix(g.pattern, "./")
+		}
+
+		if err != nil {
+			h.logger.Errorf("golangci-lint-langserver: linting %s: %s", name, err)
+			failed = append(failed, name)
+
+			continue
+		}
+
+		for _, issue := range r.Issues {
+			issue.Pos.Filename = g.cfg.issuePath(issue.Pos.Filename)
+			result.Issues = append(result.Issues, issue)
+		}
+	}
+
+	if len(failed) == len(groups) {
+		return nil, cause
+	}
+
+	if len(failed) > 0 {
+		h.showMessage(MTWarning, fmt.Sprintf("golangci-lint-langserver: %s failed to lint, the rest of the workspace was linted", strings.Join(failed, ", ")))
+	}
+
+	return &result, nil
+}
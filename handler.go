@@ -1,111 +1,640 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os/exec"
-	"strings"
+	"path"
+	"path/filepath"
+	"runtime/debug"
+	"sync"
+	"time"
 
 	"github.com/sourcegraph/jsonrpc2"
 )
 
-func NewHandler(logger logger) jsonrpc2.Handler {
+func NewHandler(logger logger, shared *sharedState) jsonrpc2.Handler {
+	return newLangHandler(logger, shared).handler()
+}
+
+func newLangHandler(logger logger, shared *sharedState) *langHandler {
+	ctx, cancel := context.WithCancel(context.Background())
+
 	handler := &langHandler{
-		logger:  logger,
-		request: make(chan DocumentURI),
+		logger: logger,
+		queue:  newLintQueue(),
+		intake: newIntake(),
+		done:   make(chan struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+		cfg:    &config{},
+		docs:   make(map[DocumentURI]*document),
+
+		published: make(map[DocumentURI][]Diagnostic),
+		cache:     shared.cache,
+		trees:     shared.trees,
+		packages:  shared.packages,
+		runs:      shared.runs,
+		updates:   shared.updates,
+		fixtures:  shared.fixtures,
+		stdio:     shared.stdio,
+		known:     make(map[string]map[string][]Diagnostic),
+		lastUsed:  make(map[string]time.Time),
+
+		gitIgnoredFiles: make(map[string]bool),
+		issueCounts:     make(map[DocumentURI]map[string]int),
+		progresses:      make(map[ProgressToken]*progress),
+		linterStats:     make(map[string]LinterStats),
+		running:         make(map[*runningLint]struct{}),
+		telemetry:       newTelemetry(),
+		trustAnswers:    make(map[string]bool),
 	}
+	handler.debouncer = newDebouncer(handler)
 	go handler.linter()
+	go handler.resolver()
 
-	return jsonrpc2.HandlerWithError(handler.handle)
+	return handler
 }
 
-type langHandler struct {
-	logger  logger
-	conn    *jsonrpc2.Conn
-	request chan DocumentURI
-	command []string
+func (h *langHandler) handler() jsonrpc2.Handler {
+	return &concurrentHandler{Handler: jsonrpc2.HandlerWithError(h.handle), cancels: make(map[jsonrpc2.ID]context.CancelFunc)}
+}
+
+const shutdownTimeout = 5 * time.Second
+
+// concurrentHandler handles requests concurrently, while notifications are
+// handled in the order they are received since their effects depend on it.
+// The context of a request is cancelled by $/cancelRequest.
+type concurrentHandler struct {
+	jsonrpc2.Handler
 
-	rootURI string
+	// mu guards cancels, which cancel the requests in progress.
+	mu      sync.Mutex
+	cancels map[jsonrpc2.ID]context.CancelFunc
 }
 
-func (h *langHandler) lint(uri DocumentURI) ([]Diagnostic, error) {
-	diagnostics := make([]Diagnostic, 0)
+func (h *concurrentHandler) Handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if req.Notif {
+		if req.Method == "$/cancelRequest" {
+			h.cancelRequest(req)
+
+			return
+		}
+
+		h.Handler.Handle(ctx, conn, req)
+
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	h.mu.Lock()
+	h.cancels[req.ID] = cancel
+	h.mu.Unlock()
+
+	go func() {
+		defer func() {
+			h.mu.Lock()
+			delete(h.cancels, req.ID)
+			h.mu.Unlock()
+
+			cancel()
+		}()
+
+		h.Handler.Handle(ctx, conn, req)
+	}()
+}
+
+// CancelParams are the params of $/cancelRequest.
+type CancelParams struct {
+	ID jsonrpc2.ID `json:"id"`
+}
+
+func (h *concurrentHandler) cancelRequest(req *jsonrpc2.Request) {
+	var params CancelParams
+	if req.Params == nil || json.Unmarshal(*req.Params, &params) != nil {
+		return
+	}
+
+	h.mu.Lock()
+	cancel, ok := h.cancels[params.ID]
+	h.mu.Unlock()
+
+	if ok {
+		cancel()
+	}
+}
+
+type langHandler struct {
+	logger    logger
+	queue     *lintQueue
+	intake    *intake
+	debouncer *debouncer
+	// done is closed when the linter goroutine has finished.
+	done chan struct{}
+
+	// ctx is cancelled on shutdown to kill running golangci-lint processes.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// paramsMu serializes the changes of the workspace folders with the
+	// reloads building the config from them.
+	paramsMu sync.Mutex
+
+	// docsMu guards docs, the documents opened in the client.
+	docsMu sync.Mutex
+	docs   map[DocumentURI]*document
+
+	cache     *resultCache
+	trees     *treeCache
+	telemetry *telemetry
+
+	// knownMu guards known, the diagnostics of the latest run per working
+	// directory, keyed by normalized path, and lastUsed, when each file was
+	// last open.
+	knownMu  sync.Mutex
+	known    map[string]map[string][]Diagnostic
+	lastUsed map[string]time.Time
+
+	// pubMu guards published, the diagnostics last published per file, and
+	// issueCounts, their number per linter before capping.
+	pubMu       sync.Mutex
+	published   map[DocumentURI][]Diagnostic
+	issueCounts map[DocumentURI]map[string]int
+
+	// ignoredMu guards gitIgnoredFiles, which tells per file whether git
+	// ignores it.
+	ignoredMu       sync.Mutex
+	gitIgnoredFiles map[string]bool
+
+	// packages, cache and runs are shared with the other sessions.
+	packages *packageCache
+	runs     *runGroup
+	updates  *updateChecker
+	fixtures *fixtureSet
+
+	// stdio is set when the session is the only one, served over stdio.
+	stdio bool
+
+	// mu guards cfg.
+	mu  sync.RWMutex
+	cfg *config
+
+	lastFailure string
+
+	// progressMu guards progresses, the cancellable tasks reported to the
+	// client.
+	progressMu sync.Mutex
+	progresses map[ProgressToken]*progress
+
+	// outputMu guards lastOutput, the raw output of the last run, and
+	// outputFile, the temporary file it was last shown from.
+	outputMu   sync.Mutex
+	lastOutput *runOutput
+	outputFile string
+
+	// statsMu guards recentStats, the stats of the latest runs, and
+	// linterStats, the time taken per linter over the session.
+	statsMu     sync.Mutex
+	recentStats []RunStats
+	linterStats map[string]LinterStats
+
+	// mutesMu guards muted, the issues muted in the workspace.
+	mutesMu sync.Mutex
+	muted   *muteList
+
+	// trustMu guards trustAnswers, telling by hash the workspace
+	// configurations the user was asked to trust in the session and
+	// whether they are. trustWorkspace trusts them all, as when linting
+	// once in CI.
+	trustMu        sync.Mutex
+	trustAnswers   map[string]bool
+	trustWorkspace bool
+
+	// runningMu guards running, the lints in progress.
+	runningMu sync.Mutex
+	running   map[*runningLint]struct{}
+
+	// runMu serializes golangci-lint runs.
+	runMu sync.Mutex
+}
+
+// run executes command and decodes its output. Runs that golangci-lint
+// reports as failed are returned as *toolError.
+func (h *langHandler) run(ctx context.Context, cfg *config, command []string) (*GolangCILintResult, error) {
+	if len(command) == 0 {
+		return nil, fmt.Errorf("%w for backend %s", errNoCommand, cfg.backendName)
+	}
+
+	if cfg.fixtures != nil {
+		return h.replay(ctx, cfg, command)
+	}
+
+	command, err := cfg.sandboxed(command)
+	if err != nil {
+		return nil, err
+	}
 
 	//nolint:gosec
-	cmd := exec.Command(h.command[0], h.command[1:]...)
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Dir = cfg.workingDir()
+	cmd.Env = cfg.commandEnv()
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	h.logger.Event(levelDebug, "golangci-lint-langserver: running golangci-lint", logFields{
+		"dir":     cmd.Dir,
+		"dirFrom": cfg.workingDirSource(),
+	})
 
-	b, err := cmd.CombinedOutput()
-	if err == nil {
-		return diagnostics, nil
+	if err := cmd.Start(); err != nil {
+		return nil, err
 	}
 
-	var result GolangCILintResult
-	if err := json.Unmarshal(b, &result); err != nil {
-		return diagnostics, err
+	var (
+		result    = GolangCILintResult{buckets: newDiagnosticBuckets(cfg)}
+		decodeErr error
+	)
+
+	// output keeps the end of stdout for showOutput, and all of it for
+	// backends decoding it together with stderr
+	var output outputBuffer = newTailBuffer(shownOutputSize)
+
+	_, combined := cfg.backend.(combinedBackend)
+	if combined {
+		output = &bytes.Buffer{}
+	} else {
+		decodeErr = cfg.backend.decode(io.TeeReader(stdout, output), &result)
 	}
 
-	h.logger.DebugJSON("golangci-lint-langserver: result:", result)
+	// drain the rest of the output so that the process does not block on a full pipe
+	_, _ = io.Copy(output, stdout)
 
-	for _, issue := range result.Issues {
-		issue := issue
+	exitCode := exitCodeSuccess
 
-		if strings.TrimPrefix(string(uri), h.rootURI+"/") != issue.Pos.Filename {
-			continue
+	if err := cmd.Wait(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, err
 		}
 
-		//nolint:gomnd
-		d := Diagnostic{
-			Range: Range{
-				Start: Position{Line: issue.Pos.Line - 1, Character: issue.Pos.Column - 1},
-				End:   Position{Line: issue.Pos.Line - 1, Character: issue.Pos.Column - 1},
-			},
-			Severity: DSWarning,
-			Source:   &issue.FromLinter,
-			Message:  issue.Text,
+		exitCode = exitErr.ExitCode()
+	}
+
+	h.setLastOutput(&runOutput{
+		command:  command,
+		dir:      cmd.Dir,
+		exitCode: exitCode,
+		time:     start,
+		stdout:   output.Bytes(),
+		dropped:  droppedBytes(output),
+		stderr:   stderr.Bytes(),
+	})
+	h.recordStats(cfg, cmd.Dir, start, stderr.Bytes())
+
+	if combined {
+		decodeErr = cfg.backend.decode(io.MultiReader(bytes.NewReader(output.Bytes()), bytes.NewReader(stderr.Bytes())), &result)
+	}
+
+	h.logger.Event(levelDebug, "golangci-lint-langserver: golangci-lint finished", logFields{
+		"dir":      cmd.Dir,
+		"exitCode": exitCode,
+		"duration": time.Since(start).String(),
+	})
+
+	return runResult(cfg, exitCode, &result, decodeErr, stderr.String())
+}
+
+// runResult returns the result of a run of the backend of cfg that exited with
+// exitCode, from its decoded output.
+func runResult(cfg *config, exitCode int, result *GolangCILintResult, decodeErr error, stderr string) (*GolangCILintResult, error) {
+	switch cfg.backend.classify(cfg, exitCode) {
+	case runClean:
+		return &GolangCILintResult{}, nil
+	case runIssues:
+		if decodeErr != nil && exitCode == exitCodeSuccess {
+			return &GolangCILintResult{}, nil
+		}
+
+		if decodeErr != nil {
+			return nil, fmt.Errorf("%w: %s", decodeErr, stderr)
+		}
+
+		if result.Report.Error == "" {
+			return result, nil
 		}
-		diagnostics = append(diagnostics, d)
 	}
 
-	return diagnostics, nil
+	return nil, &toolError{Tool: cfg.backendName, ExitCode: exitCode, Report: result.Report.Error, Stderr: stderr}
+}
+
+// timedRun runs command and records the run in the metrics and telemetry.
+// Transient failures are retried once with a config working around them, and
+// configs with variants run once per variant.
+func (h *langHandler) timedRun(ctx context.Context, cfg *config, command commandFunc) (*GolangCILintResult, error) {
+	if len(cfg.variants) > 0 && cfg.variant == nil {
+		return h.runVariants(ctx, cfg, command)
+	}
+
+	start := time.Now()
+	result, err := h.retryParallel(func() (*GolangCILintResult, error) {
+		return h.run(ctx, cfg, command(cfg))
+	})
+
+	if retry, ok := h.retryTransient(ctx, cfg, command, err); ok {
+		result, err = h.retryParallel(func() (*GolangCILintResult, error) {
+			return h.run(ctx, retry, command(retry))
+		})
+	}
+
+	d, cancelled := time.Since(start), ctx.Err() != nil
+
+	recordRun(d, err, cancelled)
+	h.recordTelemetry(cfg, d, err, cancelled)
+
+	return result, err
+}
+
+// cachedRun runs golangci-lint unless none of the files the run depends on, in
+// the package in dir or elsewhere in the working directory, changed since the
+// last successful run. stale is set when the result was
+// persisted by a previous server and should be refreshed.
+func (h *langHandler) cachedRun(ctx context.Context, cfg *config, dir string) (result *GolangCILintResult, stale bool, err error) {
+	hash, err := cfg.packageHash(dir, h.trees)
+	if err != nil {
+		h.logger.Debugf("golangci-lint-langserver: hashing %s: %s", dir, err)
+	} else if result, persisted, ok := h.cache.get(dir, hash); ok {
+		h.logger.Debugf("golangci-lint-langserver: using cached result for %s", dir)
+
+		return result, persisted, nil
+	}
+
+	if hash == "" {
+		result, err = h.timedRun(ctx, cfg, lintArgs())
+
+		return result, false, err
+	}
+
+	// another session may be running the same lint
+	result, err = h.runs.do(ctx, runKey(cfg, dir, hash), func(ctx context.Context) (*GolangCILintResult, error) {
+		result, err := h.timedRun(ctx, cfg, lintArgs())
+		if err == nil {
+			h.cache.put(dir, hash, result)
+			h.saveCache(cfg)
+		}
+
+		return result, err
+	})
+
+	return result, false, err
+}
+
+// lint lints the package of uri and returns the diagnostics of the run
+// grouped by the normalized path of their file. Fast runs are merged with the
+// latest full run instead of replacing it.
+func (h *langHandler) lint(ctx context.Context, uri DocumentURI, mode lintMode) (map[string][]Diagnostic, error) {
+	start := time.Now()
+	filename := uriToPath(string(uri))
+
+	var (
+		result *GolangCILintResult
+		stale  bool
+		err    error
+	)
+
+	h.runMu.Lock()
+	// the config is read while holding runMu as installations update it
+	cfg := h.config().forFile(filename)
+	switch {
+	case cfg.report != nil:
+		result, err = cfg.report.load()
+		mode = modeFull
+	case mode == modeFast:
+		result, err = h.timedRun(ctx, cfg, lintArgs(cfg.fastFlag()))
+	default:
+		result, stale, err = h.cachedRun(ctx, cfg, filepath.Dir(filename))
+	}
+	h.runMu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if stale {
+		// lint again once the cached diagnostics are published
+		go h.enqueue(uri, priorityBackground)
+	}
+
+	h.logger.DebugJSON("golangci-lint-langserver: result:", result)
+
+	files := cfg.diagnosticsByFile(result)
+	if mode == modeFast {
+		files = h.mergeFast(cfg.workingDir(), result, files)
+	} else {
+		h.remember(cfg.workingDir(), files, cfg.maxTrackedFiles)
+	}
+
+	h.logger.Event(levelDebug, "golangci-lint-langserver: linted", logFields{
+		"uri":      uri,
+		"files":    len(files),
+		"fast":     mode == modeFast,
+		"duration": time.Since(start).String(),
+	})
+
+	return files, nil
+}
+
+// issuePath returns the absolute local path of a filename reported by
+// golangci-lint, translating remote paths back to local paths.
+func (c *config) issuePath(filename string) string {
+	if c.executor != nil {
+		if !path.IsAbs(filename) && c.executor.workdir() != "" {
+			filename = path.Join(c.executor.workdir(), filename)
+		}
+
+		filename = c.executor.toLocal(filename)
+	}
+
+	if !filepath.IsAbs(filename) {
+		filename = filepath.Join(c.workingDir(), filename)
+	}
+
+	return filepath.Clean(filename)
+}
+
+// enqueue requests a full lint of uri with priority prio.
+func (h *langHandler) enqueue(uri DocumentURI, prio priority) {
+	h.schedule(uri, prio, modeFull)
+}
+
+// schedule requests a lint of uri without blocking. Requests made after
+// shutdown are ignored.
+func (h *langHandler) schedule(uri DocumentURI, prio priority, mode lintMode) {
+	if dropped, ok := h.intake.push(uri, prio, mode); ok {
+		h.logger.Printf("golangci-lint-langserver: too many lint requests, dropped the one of %s", dropped)
+	}
 }
 
 func (h *langHandler) linter() {
+	defer close(h.done)
+
 	for {
-		uri, ok := <-h.request
+		pkg, uris, mode, ok := h.queue.pop()
 		if !ok {
 			break
 		}
 
-		diagnostics, err := h.lint(uri)
-		if err != nil {
-			h.logger.Printf("%s", err)
+		h.lintAndPublish(pkg, uris, mode)
+	}
+}
 
-			continue
+// lintAndPublish lints pkg once and publishes the diagnostics of uris and of
+// every other open file of pkg. A panic is recovered and reported so that the
+// linter keeps serving later requests.
+func (h *langHandler) lintAndPublish(pkg string, uris []DocumentURI, mode lintMode) {
+	defer func() {
+		if r := recover(); r != nil {
+			h.logger.Errorf("golangci-lint-langserver: panic while linting %s: %v\n%s", pkg, r, debug.Stack())
+			h.showMessage(MTError, fmt.Sprintf("golangci-lint-langserver: internal error while linting %s: %v", pkg, r))
 		}
+	}()
 
-		if err := h.conn.Notify(
-			context.Background(),
-			"textDocument/publishDiagnostics",
-			&PublishDiagnosticsParams{
-				URI:         uri,
-				Diagnostics: diagnostics,
-			}); err != nil {
-			h.logger.Printf("%s", err)
+	uris = h.packageFiles(pkg, uris)
+	targets := make([]DocumentURI, 0, len(uris))
+	changes := h.gitChanges(h.config())
+
+	for _, uri := range uris {
+		filename := uriToPath(string(uri))
+
+		if h.excluded(h.config().forFile(filename), uri) || changes != nil && !changes.dirty(filename) {
+			h.publish(uri, nil, []Diagnostic{})
+		} else {
+			targets = append(targets, uri)
 		}
 	}
+
+	if len(targets) == 0 {
+		return
+	}
+
+	// the client discards the diagnostics if the documents change meanwhile
+	versions := h.diskVersions(targets)
+
+	ctx, cancel := context.WithCancel(h.ctx)
+	defer cancel()
+
+	start := time.Now()
+	h.notifyStarted(pkg, targets, mode)
+	p := h.beginProgress("golangci-lint", pkg, cancel)
+
+	done := h.startRunning(pkg, targets, mode)
+	files, err := h.lint(ctx, targets[0], mode)
+	done()
+	if err != nil && ctx.Err() != nil {
+		err = errCancelled
+	}
+
+	h.notifyFinished(pkg, start, err)
+
+	if err != nil {
+		p.end(err.Error())
+
+		if h.ctx.Err() != nil {
+			// the run was killed by shutdown
+			return
+		}
+
+		if errors.Is(err, errCancelled) {
+			h.logger.Printf("golangci-lint-langserver: lint of %s cancelled", pkg)
+
+			return
+		}
+
+		h.reportFailure(err)
+		h.publishConfigError(h.config().forFile(uriToPath(string(targets[0]))), err)
+
+		return
+	}
+
+	p.end("")
+
+	h.lastFailure = ""
+	h.publishConfigError(h.config().forFile(uriToPath(string(targets[0]))), nil)
+
+	for _, uri := range targets {
+		filename := uriToPath(string(uri))
+
+		diagnostics, ok := files[normalizePath(filename)]
+		if !ok {
+			diagnostics = []Diagnostic{}
+		}
+
+		if changes != nil && h.config().gitMode == gitChangedLines {
+			diagnostics = changes.filterChangedLines(filename, diagnostics)
+		}
+
+		version := versions[uri]
+
+		switch {
+		case h.rewritten(uri):
+			// the diagnostics are for the content on disk, not for the
+			// version of the document that was saved
+			h.logger.Debugf("golangci-lint-langserver: %s changed on disk since it was saved", uri)
+
+			version = nil
+		case version != nil && !h.matchesDisk(uri):
+			// the diagnostics are for the content on disk, which no longer
+			// matches the document, as when an idle run lints changes that
+			// are not saved
+			h.logger.Debugf("golangci-lint-langserver: %s differs from its file", uri)
+
+			version = nil
+		}
+
+		h.publish(uri, version, diagnostics)
+	}
 }
 
 func (h *langHandler) handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (result interface{}, err error) {
 	h.logger.DebugJSON("golangci-lint-langserver: request:", req)
 
+	start := time.Now()
+
+	defer func() {
+		// the client no longer waits for the result of a cancelled request
+		if !req.Notif && ctx.Err() != nil && h.ctx.Err() == nil {
+			result, err = nil, &jsonrpc2.Error{Code: CodeRequestCancelled, Message: "request cancelled"}
+		}
+
+		fields := logFields{"method": req.Method, "duration": time.Since(start).String()}
+		if err != nil {
+			fields["error"] = err.Error()
+		}
+
+		h.logger.Event(levelDebug, "golangci-lint-langserver: handled", fields)
+	}()
+
 	switch req.Method {
 	case "initialize":
 		return h.handleInitialize(ctx, conn, req)
 	case "initialized":
-		return
+		return h.handleInitialized(ctx, conn, req)
 	case "shutdown":
 		return h.handleShutdown(ctx, conn, req)
+	case "exit":
+		return h.handleExit(ctx, conn, req)
 	case "textDocument/didOpen":
 		return h.handleTextDocumentDidOpen(ctx, conn, req)
 	case "textDocument/didClose":
@@ -114,6 +643,42 @@ func (h *langHandler) handle(ctx context.Context, conn *jsonrpc2.Conn, req *json
 		return h.handleTextDocumentDidChange(ctx, conn, req)
 	case "textDocument/didSave":
 		return h.handleTextDocumentDidSave(ctx, conn, req)
+	case "textDocument/codeAction":
+		return h.handleTextDocumentCodeAction(ctx, conn, req)
+	case "codeAction/resolve":
+		return h.handleCodeActionResolve(ctx, conn, req)
+	case "textDocument/completion":
+		return h.handleTextDocumentCompletion(ctx, conn, req)
+	case "textDocument/hover":
+		return h.handleTextDocumentHover(ctx, conn, req)
+	case "textDocument/codeLens":
+		return h.handleTextDocumentCodeLens(ctx, conn, req)
+	case "textDocument/diagnostic":
+		return h.handleTextDocumentDiagnostic(ctx, conn, req)
+	case "window/workDoneProgress/cancel":
+		return h.handleWorkDoneProgressCancel(ctx, conn, req)
+	case "workspace/didChangeWorkspaceFolders":
+		return h.handleWorkspaceDidChangeWorkspaceFolders(ctx, conn, req)
+	case "workspace/willRenameFiles":
+		return h.handleWorkspaceWillRenameFiles(ctx, conn, req)
+	case "workspace/didRenameFiles":
+		return h.handleWorkspaceDidRenameFiles(ctx, conn, req)
+	case "workspace/didDeleteFiles":
+		return h.handleWorkspaceDidDeleteFiles(ctx, conn, req)
+	case "workspace/didChangeWatchedFiles":
+		return h.handleWorkspaceDidChangeWatchedFiles(ctx, conn, req)
+	case "golangci-lint/linters":
+		return h.handleLinters(ctx, conn, req)
+	case "golangci-lint/effectiveConfig":
+		return h.handleEffectiveConfig(ctx, conn, req)
+	case "golangci-lint/queueStatus":
+		return h.handleQueueStatus(ctx, conn, req)
+	case "golangci-lint/stats":
+		return h.handleStats(ctx, conn, req)
+	case "golangci-lint/setLinters":
+		return h.handleSetLinters(ctx, conn, req)
+	case "workspace/executeCommand":
+		return h.handleWorkspaceExecuteCommand(ctx, conn, req)
 	}
 
 	return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeMethodNotFound, Message: fmt.Sprintf("method not supported: %s", req.Method)}
@@ -125,43 +690,166 @@ func (h *langHandler) handleInitialize(_ context.Context, conn *jsonrpc2.Conn, r
 		return nil, err
 	}
 
-	h.rootURI = params.RootURI
-	h.conn = conn
-	h.command = params.InitializationOptions.Command
+	cfg := h.newConfig(conn, &params)
+	h.setConfig(cfg)
+	h.loadCache(cfg)
+
+	// the client of a daemon session may run on another machine, and its
+	// exit must not end the other sessions
+	if params.ProcessID != nil && *params.ProcessID > 0 && h.stdio {
+		go h.watchParent(*params.ProcessID, conn)
+	}
+
+	var codeLens *CodeLensOptions
+	if cfg.codeLens {
+		codeLens = &CodeLensOptions{}
+	}
+
+	var diagnostic *DiagnosticOptions
+	if cfg.pullsDiagnostics() {
+		diagnostic = &DiagnosticOptions{Identifier: "golangci-lint", InterFileDependencies: true}
+	}
+
+	var codeAction interface{} = true
+	if cfg.resolveEdits {
+		codeAction = &CodeActionOptions{ResolveProvider: true}
+	}
 
 	return InitializeResult{
 		Capabilities: ServerCapabilities{
 			TextDocumentSync: TextDocumentSyncOptions{
-				Change:    TDSKNone,
+				Change:    TDSKFull,
 				OpenClose: true,
-				Save:      true,
+				Save:      &SaveOptions{IncludeText: true},
+			},
+			CompletionProvider: &CompletionProvider{TriggerCharacters: []string{" "}},
+			HoverProvider:      true,
+			CodeActionProvider: codeAction,
+			ExecuteCommandProvider: &ExecuteCommandOptions{
+				Commands: []string{commandLint, commandShowOutput, commandMute, commandUnmute},
+			},
+			CodeLensProvider:   codeLens,
+			DiagnosticProvider: diagnostic,
+			Workspace: &WorkspaceOptions{
+				WorkspaceFolders: &WorkspaceFoldersOptions{Supported: true, ChangeNotifications: true},
+				FileOperations: &FileOperationsOptions{
+					WillRename: fileOperations,
+					DidRename:  fileOperations,
+					DidDelete:  fileOperations,
+				},
 			},
 		},
 	}, nil
 }
 
+func (h *langHandler) handleInitialized(_ context.Context, _ *jsonrpc2.Conn, _ *jsonrpc2.Request) (result interface{}, err error) {
+	cfg := h.config()
+
+	// hold runMu so that lints wait for the installation to finish and for
+	// the version selecting the flags of golangci-lint
+	h.runMu.Lock()
+
+	go func() {
+		if cfg.install != nil {
+			if err := h.ensureInstalled(*cfg.install); err != nil {
+				h.logger.Errorf("golangci-lint-langserver: installing golangci-lint: %s", err)
+			}
+		}
+
+		h.checkVersion()
+		h.runMu.Unlock()
+
+		if h.updates != nil {
+			h.notifyUpdate()
+		}
+
+		// lint the workspace with the commands the user trusts
+		h.confirmTrust(cfg)
+		h.startWorkspace(h.config())
+	}()
+
+	go h.watchConfig()
+
+	if cfg.watchDeletes {
+		go h.registerWatchers(cfg)
+	}
+
+	return nil, nil
+}
+
 func (h *langHandler) handleShutdown(_ context.Context, _ *jsonrpc2.Conn, _ *jsonrpc2.Request) (result interface{}, err error) {
-	close(h.request)
+	// kill running lints first so that pending requests are not served
+	h.close()
+
+	// wait for diagnostics already computed to be published
+	select {
+	case <-h.done:
+	case <-time.After(shutdownTimeout):
+	}
 
 	return nil, nil
 }
 
+func (h *langHandler) handleExit(_ context.Context, conn *jsonrpc2.Conn, _ *jsonrpc2.Request) (result interface{}, err error) {
+	h.cancel()
+
+	return nil, conn.Close()
+}
+
 func (h *langHandler) handleTextDocumentDidOpen(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (result interface{}, err error) {
 	var params DidOpenTextDocumentParams
 	if err := json.Unmarshal(*req.Params, &params); err != nil {
 		return nil, err
 	}
 
-	h.request <- params.TextDocument.URI
+	h.openDocument(params.TextDocument)
+	// the client may have dropped the diagnostics when the file was closed
+	h.forgetPublished(params.TextDocument.URI)
+
+	// show what earlier runs found right away; the lint below refreshes it
+	if diagnostics, ok := h.knownDiagnostics(params.TextDocument.URI); ok {
+		h.publish(params.TextDocument.URI, nil, diagnostics)
+	}
+
+	if h.config().triggers.open {
+		h.enqueue(params.TextDocument.URI, priorityInteractive)
+	}
 
 	return nil, nil
 }
 
-func (h *langHandler) handleTextDocumentDidClose(_ context.Context, _ *jsonrpc2.Conn, _ *jsonrpc2.Request) (result interface{}, err error) {
+func (h *langHandler) handleTextDocumentDidClose(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (result interface{}, err error) {
+	var params DidCloseTextDocumentParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, err
+	}
+
+	h.debouncer.cancel(params.TextDocument.URI)
+	h.closeDocument(params.TextDocument.URI)
+	h.touch(params.TextDocument.URI)
+
 	return nil, nil
 }
 
-func (h *langHandler) handleTextDocumentDidChange(_ context.Context, _ *jsonrpc2.Conn, _ *jsonrpc2.Request) (result interface{}, err error) {
+func (h *langHandler) handleTextDocumentDidChange(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (result interface{}, err error) {
+	var params DidChangeTextDocumentParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, err
+	}
+
+	h.changeDocument(&params)
+
+	if !h.isGoDocument(params.TextDocument.URI) {
+		return nil, nil
+	}
+
+	switch cfg := h.config(); {
+	case cfg.triggers.change:
+		h.debouncer.changed(params.TextDocument.URI, cfg.idleDelay, modeFull)
+	case cfg.strategy == strategyTwoTier && !cfg.triggers.manualOnly():
+		h.debouncer.changed(params.TextDocument.URI, cfg.changeDelay, modeFast)
+	}
+
 	return nil, nil
 }
 
@@ -171,7 +859,33 @@ func (h *langHandler) handleTextDocumentDidSave(_ context.Context, _ *jsonrpc2.C
 		return nil, err
 	}
 
-	h.request <- params.TextDocument.URI
+	h.trees.forget()
+
+	if params.Text != nil {
+		h.saveDocument(params.TextDocument.URI, *params.Text)
+	}
+
+	if !h.config().triggers.save {
+		return nil, nil
+	}
+
+	if !h.isGoDocument(params.TextDocument.URI) {
+		if isBuildFile(params.TextDocument.URI) {
+			for _, uri := range h.openDocuments() {
+				h.enqueue(uri, priorityBackground)
+			}
+		}
+
+		return nil, nil
+	}
+
+	// the full run supersedes a pending fast one
+	h.debouncer.cancel(params.TextDocument.URI)
+	h.enqueue(params.TextDocument.URI, priorityInteractive)
+
+	if h.config().lintDependents {
+		go h.enqueueDependents(params.TextDocument.URI)
+	}
 
 	return nil, nil
 }
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+func (h *langHandler) handleTextDocumentHover(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (result interface{}, err error) {
+	var params TextDocumentPositionParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, err
+	}
+
+	text, ok := h.documentText(params.TextDocument.URI)
+	if !ok || !isLintConfig(params.TextDocument.URI) {
+		return nil, nil
+	}
+
+	cfg := h.config()
+	root, v2 := cfg.lintConfigSchema(text)
+
+	line := lineAt(text, params.Position.Line)
+	start, end := configWord(line, byteOffset(line, params.Position.Character))
+
+	if start == end {
+		return nil, nil
+	}
+
+	doc, ok := configDoc(root, v2, text, params.Position.Line, line, start, end)
+	if !ok {
+		return nil, nil
+	}
+
+	return &Hover{
+		Contents: MarkupContent{Kind: "markdown", Value: doc},
+		Range: &Range{
+			Start: Position{Line: params.Position.Line, Character: utf16Offset(line, start)},
+			End:   Position{Line: params.Position.Line, Character: utf16Offset(line, end)},
+		},
+	}, nil
+}
+
+// configWord returns the bounds of the key or name at offset in line.
+func configWord(line string, offset int) (start, end int) {
+	isWord := func(c byte) bool {
+		return c == '-' || c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+	}
+
+	start, end = offset, offset
+	for start > 0 && isWord(line[start-1]) {
+		start--
+	}
+
+	for end < len(line) && isWord(line[end]) {
+		end++
+	}
+
+	// a dash starting the word is a sequence item
+	for start < end && line[start] == '-' {
+		start++
+	}
+
+	return start, end
+}
+
+// configDoc documents the key or linter name between start and end of line
+// n of the configuration text.
+func configDoc(root *configKey, v2 bool, text string, n int, line string, start, end int) (string, bool) {
+	word := line[start:end]
+	isKey := strings.HasPrefix(line[end:], ":")
+
+	pos := locateYAML(text, n, start)
+
+	parent := root.lookup(pos.path)
+	if parent == nil {
+		return "", false
+	}
+
+	var list listKind
+
+	switch {
+	case isKey && parent.settings != listNone:
+		list = parent.settings
+	case isKey && pos.item:
+		for i := range parent.item {
+			if parent.item[i].name == word {
+				return parent.item[i].markdown(), true
+			}
+		}
+
+		return "", false
+	case isKey:
+		if k := parent.child(word); k != nil {
+			return k.markdown(), true
+		}
+
+		return "", false
+	case pos.item:
+		list = parent.list
+	}
+
+	if list != listLinters && list != listFormatters {
+		return "", false
+	}
+
+	for i := range lintLinters {
+		if lintLinters[i].name == word {
+			return lintLinters[i].markdown(v2), true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	installMethodDownload = "download"
+	installMethodGo       = "go"
+	installMethodGoTool   = "goTool"
+	installMethodGoRun    = "goRun"
+
+	golangciLintModule = "github.com/golangci/golangci-lint/cmd/golangci-lint"
+	releaseURLFormat   = "https://github.com/golangci/golangci-lint/releases/download/v%[1]s/golangci-lint-%[1]s-%[2]s-%[3]s.%[4]s"
+	checksumsURLFormat = "https://github.com/golangci/golangci-lint/releases/download/v%[1]s/golangci-lint-%[1]s-checksums.txt"
+)
+
+var (
+	errNoChecksum       = errors.New("no checksum published")
+	errChecksumMismatch = errors.New("checksum mismatch")
+)
+
+type InstallOptions struct {
+	Version string `json:"version"`
+	Method  string `json:"method,omitempty"`
+	Dir     string `json:"dir,omitempty"`
+}
+
+func binaryName() string {
+	if runtime.GOOS == "windows" {
+		return "golangci-lint.exe"
+	}
+
+	return "golangci-lint"
+}
+
+func (o InstallOptions) dir() (string, error) {
+	if o.Dir != "" {
+		return filepath.Join(o.Dir, o.Version), nil
+	}
+
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(cache, "golangci-lint-langserver", o.Version), nil
+}
+
+// ensureInstalled makes sure the configured golangci-lint binary has the
+// pinned version, installing it when it is missing or outdated, and rewrites
+// the lint command to use it.
+func (h *langHandler) ensureInstalled(opts InstallOptions) error {
+	opts.Version = strings.TrimPrefix(opts.Version, "v")
+
+	command := h.config().command
+	if len(command) == 0 {
+		return errNoCommand
+	}
+
+	switch opts.Method {
+	case installMethodGoTool:
+		h.setCommand(append([]string{"go", "tool", "golangci-lint"}, command[1:]...))
+
+		return nil
+	case installMethodGoRun:
+		h.setCommand(append([]string{"go", "run", golangciLintPackage(opts.Version) + "@v" + opts.Version}, command[1:]...))
+
+		return nil
+	}
+
+	if bin, err := exec.LookPath(command[0]); err == nil {
+		if info, err := detectVersion(bin); err == nil && compareVersions(info.Version, opts.Version) == 0 {
+			return nil
+		}
+	}
+
+	dir, err := opts.dir()
+	if err != nil {
+		return err
+	}
+
+	bin := filepath.Join(dir, binaryName())
+
+	if info, err := detectVersion(bin); err == nil && compareVersions(info.Version, opts.Version) == 0 {
+		h.setCommand(append([]string{bin}, command[1:]...))
+
+		return nil
+	}
+
+	p := h.beginProgress("Installing golangci-lint", "v"+opts.Version, nil)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		p.end(err.Error())
+
+		return err
+	}
+
+	switch opts.Method {
+	case installMethodGo:
+		err = goInstall(opts.Version, dir)
+	default:
+		err = download(opts.Version, bin, p)
+	}
+
+	if err != nil {
+		p.end(err.Error())
+
+		return err
+	}
+
+	p.end("installed " + bin)
+	h.setCommand(append([]string{bin}, command[1:]...))
+
+	return nil
+}
+
+func (h *langHandler) setCommand(command []string) {
+	h.updateConfig(func(c *config) {
+		c.command = command
+	})
+}
+
+func goInstall(version, dir string) error {
+	cmd := exec.Command("go", "install", golangciLintPackage(version)+"@v"+version)
+	cmd.Env = append(os.Environ(), "GOBIN="+dir)
+
+	if b, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, b)
+	}
+
+	return nil
+}
+
+func download(version, bin string, p *progress) error {
+	format := "tar.gz"
+	if runtime.GOOS == "windows" {
+		format = "zip"
+	}
+
+	url := fmt.Sprintf(releaseURLFormat, version, runtime.GOOS, runtime.GOARCH, format)
+	p.report("downloading " + url)
+
+	body, err := fetch(url)
+	if err != nil {
+		return err
+	}
+
+	checksums, err := fetch(fmt.Sprintf(checksumsURLFormat, version))
+	if err != nil {
+		return err
+	}
+
+	if err := verifyChecksum(checksums, path.Base(url), body); err != nil {
+		return err
+	}
+
+	p.report("extracting " + path.Base(url))
+
+	var r io.Reader
+	if format == "zip" {
+		r, err = extractZip(body, binaryName())
+	} else {
+		r, err = extractTarGz(body, binaryName())
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return writeExecutable(bin, r)
+}
+
+// fetch returns the body of url.
+func fetch(url string) ([]byte, error) {
+	//nolint:gosec,noctx
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// verifyChecksum checks body, the release file called name, against its
+// SHA-256 listed in checksums, a checksums.txt file of a release.
+func verifyChecksum(checksums []byte, name string, body []byte) error {
+	for _, line := range strings.Split(string(checksums), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+
+		sum := sha256.Sum256(body)
+		if !strings.EqualFold(fields[0], hex.EncodeToString(sum[:])) {
+			return fmt.Errorf("%w: %s", errChecksumMismatch, name)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", errNoChecksum, name)
+}
+
+func extractTarGz(b []byte, name string) (io.Reader, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	tr := tar.NewReader(gz)
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, errBinaryNotFound
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if path.Base(hdr.Name) == name {
+			return tr, nil
+		}
+	}
+}
+
+func extractZip(b []byte, name string) (io.Reader, error) {
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range zr.File {
+		if path.Base(f.Name) == name {
+			return f.Open()
+		}
+	}
+
+	return nil, errBinaryNotFound
+}
+
+func writeExecutable(name string, r io.Reader) error {
+	tmp := name + ".tmp"
+
+	//nolint:gosec
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o755)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, name)
+}
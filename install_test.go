@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+)
+
+func TestVerifyChecksum(t *testing.T) {
+	// the SHA-256 of "hello"
+	const sum = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+	checksums := []byte("0000000000000000000000000000000000000000000000000000000000000000  golangci-lint-1.55.2-darwin-arm64.tar.gz\n" +
+		sum + "  golangci-lint-1.55.2-linux-amd64.tar.gz\n" +
+		"2CF24DBA5FB0A30E26E83B2AC5B9E29E1B161E5C1FA7425E73043362938B9824 *golangci-lint-1.55.2-windows-amd64.zip\n")
+
+	tests := []struct {
+		name string
+		file string
+		body string
+		err  error
+	}{
+		{name: "match", file: "golangci-lint-1.55.2-linux-amd64.tar.gz", body: "hello"},
+		{name: "uppercase binary mode", file: "golangci-lint-1.55.2-windows-amd64.zip", body: "hello"},
+		{name: "mismatch", file: "golangci-lint-1.55.2-darwin-arm64.tar.gz", body: "hello", err: errChecksumMismatch},
+		{name: "tampered", file: "golangci-lint-1.55.2-linux-amd64.tar.gz", body: "hello!", err: errChecksumMismatch},
+		{name: "missing", file: "golangci-lint-1.55.2-linux-386.tar.gz", body: "hello", err: errNoChecksum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := verifyChecksum(checksums, tt.file, []byte(tt.body)); !errors.Is(err, tt.err) {
+				t.Errorf("verifyChecksum() = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestEnsureInstalledNoCommand(t *testing.T) {
+	h := newLangHandler(newStdLogger(ioutil.Discard, 0, levelError, false), newSharedState())
+	defer h.close()
+
+	h.setConfig(&config{})
+
+	for _, method := range []string{installMethodDownload, installMethodGoTool} {
+		if err := h.ensureInstalled(InstallOptions{Version: "v1.55.2", Method: method}); !errors.Is(err, errNoCommand) {
+			t.Errorf("ensureInstalled() with method %s = %v, want %v", method, err, errNoCommand)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import "sync"
+
+// maxIntake bounds the lint requests waiting for their package to be
+// resolved. Beyond it, the oldest request is dropped, background ones first.
+const maxIntake = 1024
+
+type intakeItem struct {
+	priority priority
+	mode     lintMode
+}
+
+// intake accepts lint requests without blocking, coalescing the requests of
+// a file, until the resolver moves them to the lint queue. Resolving their
+// package runs go list and git, which must not hold up the handling of
+// notifications, as the client cannot send more in the meantime.
+type intake struct {
+	mu      sync.Mutex
+	pending map[DocumentURI]intakeItem
+	order   []DocumentURI
+	// wake is signalled when requests are pending.
+	wake chan struct{}
+}
+
+func newIntake() *intake {
+	return &intake{pending: make(map[DocumentURI]intakeItem), wake: make(chan struct{}, 1)}
+}
+
+// push accepts a lint of uri, merged into the pending one if any. It returns
+// the file whose request was dropped to make room, if any.
+func (in *intake) push(uri DocumentURI, prio priority, mode lintMode) (DocumentURI, bool) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	defer func() {
+		select {
+		case in.wake <- struct{}{}:
+		default:
+		}
+	}()
+
+	if item, ok := in.pending[uri]; ok {
+		if prio > item.priority {
+			item.priority = prio
+		}
+
+		if mode == modeFull {
+			item.mode = modeFull
+		}
+
+		in.pending[uri] = item
+
+		return "", false
+	}
+
+	var (
+		dropped DocumentURI
+		ok      bool
+	)
+
+	if len(in.order) >= maxIntake {
+		i := 0
+
+		for j, u := range in.order {
+			if in.pending[u].priority == priorityBackground {
+				i = j
+
+				break
+			}
+		}
+
+		dropped, ok = in.order[i], true
+		delete(in.pending, dropped)
+		in.order = append(in.order[:i], in.order[i+1:]...)
+	}
+
+	in.pending[uri] = intakeItem{priority: prio, mode: mode}
+	in.order = append(in.order, uri)
+
+	return dropped, ok
+}
+
+// take empties the intake and returns its requests in the order they came.
+func (in *intake) take() ([]DocumentURI, map[DocumentURI]intakeItem) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	order, pending := in.order, in.pending
+	in.order, in.pending = nil, make(map[DocumentURI]intakeItem)
+
+	return order, pending
+}
+
+// resolver moves the accepted lint requests to the lint queue with their
+// package until shutdown.
+func (h *langHandler) resolver() {
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-h.intake.wake:
+		}
+
+		uris, items := h.intake.take()
+
+		for _, uri := range uris {
+			if h.skipped(uri) || h.adoptModule(uriToPath(string(uri))) {
+				continue
+			}
+
+			item := items[uri]
+			h.queue.push(h.packageOf(uriToPath(string(uri))), uri, item.priority, item.mode)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestIntakePush(t *testing.T) {
+	type push struct {
+		uri  DocumentURI
+		prio priority
+		mode lintMode
+	}
+
+	tests := []struct {
+		name    string
+		pushes  []push
+		order   []DocumentURI
+		pending map[DocumentURI]intakeItem
+	}{
+		{
+			name:    "single",
+			pushes:  []push{{"a", priorityInteractive, modeFull}},
+			order:   []DocumentURI{"a"},
+			pending: map[DocumentURI]intakeItem{"a": {priorityInteractive, modeFull}},
+		},
+		{
+			name:    "order kept",
+			pushes:  []push{{"b", priorityBackground, modeFull}, {"a", priorityBackground, modeFull}},
+			order:   []DocumentURI{"b", "a"},
+			pending: map[DocumentURI]intakeItem{"a": {priorityBackground, modeFull}, "b": {priorityBackground, modeFull}},
+		},
+		{
+			name:    "priority raised",
+			pushes:  []push{{"a", priorityBackground, modeFull}, {"b", priorityBackground, modeFull}, {"a", priorityInteractive, modeFull}},
+			order:   []DocumentURI{"a", "b"},
+			pending: map[DocumentURI]intakeItem{"a": {priorityInteractive, modeFull}, "b": {priorityBackground, modeFull}},
+		},
+		{
+			name:    "priority kept",
+			pushes:  []push{{"a", priorityInteractive, modeFull}, {"a", priorityBackground, modeFull}},
+			order:   []DocumentURI{"a"},
+			pending: map[DocumentURI]intakeItem{"a": {priorityInteractive, modeFull}},
+		},
+		{
+			name:    "full lint wins",
+			pushes:  []push{{"a", priorityInteractive, modeFast}, {"a", priorityInteractive, modeFull}, {"a", priorityInteractive, modeFast}},
+			order:   []DocumentURI{"a"},
+			pending: map[DocumentURI]intakeItem{"a": {priorityInteractive, modeFull}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := newIntake()
+
+			for _, p := range tt.pushes {
+				if dropped, ok := in.push(p.uri, p.prio, p.mode); ok {
+					t.Errorf("push(%s) dropped %s", p.uri, dropped)
+				}
+			}
+
+			if order, pending := in.take(); !reflect.DeepEqual(order, tt.order) || !reflect.DeepEqual(pending, tt.pending) {
+				t.Errorf("take() = %v, %v, want %v, %v", order, pending, tt.order, tt.pending)
+			}
+		})
+	}
+}
+
+func TestIntakePushFull(t *testing.T) {
+	uri := func(i int) DocumentURI {
+		return DocumentURI(fmt.Sprintf("file:///%d.go", i))
+	}
+
+	tests := []struct {
+		name string
+		// interactive lists the requests pushed with priorityInteractive.
+		interactive map[int]bool
+		prio        priority
+		dropped     DocumentURI
+	}{
+		{name: "oldest background", interactive: map[int]bool{0: true, 1: true}, prio: priorityInteractive, dropped: uri(2)},
+		{name: "oldest", interactive: nil, prio: priorityBackground, dropped: uri(0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := newIntake()
+
+			for i := 0; i < maxIntake; i++ {
+				prio := priorityBackground
+				if tt.interactive[i] {
+					prio = priorityInteractive
+				}
+
+				in.push(uri(i), prio, modeFull)
+			}
+
+			dropped, ok := in.push(uri(maxIntake), tt.prio, modeFull)
+			if !ok || dropped != tt.dropped {
+				t.Fatalf("push() dropped %q, %v, want %q", dropped, ok, tt.dropped)
+			}
+
+			order, pending := in.take()
+			if len(order) != maxIntake || len(pending) != maxIntake {
+				t.Errorf("%d requests left in order, %d pending, want %d", len(order), len(pending), maxIntake)
+			}
+
+			if _, ok := pending[tt.dropped]; ok {
+				t.Errorf("dropped request %s still pending", tt.dropped)
+			}
+		})
+	}
+}
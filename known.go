@@ -0,0 +1,270 @@
+package main
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// diagnosticData is attached to the published diagnostics, so that code
+// actions can tell the issue a diagnostic was created from.
+type diagnosticData struct {
+	Linter string `json:"linter"`
+	Text   string `json:"text"`
+	// DuplicateOf names the server reporting the issue as well.
+	DuplicateOf string `json:"duplicateOf,omitempty"`
+	// Linters lists all the linters reporting the issue when duplicates are
+	// merged.
+	Linters []string `json:"linters,omitempty"`
+	// Fix holds the edits fixing the issue, per file.
+	Fix []fileFix `json:"fix,omitempty"`
+	// Variants are the build variants reporting the issue, when not all
+	// of them do.
+	Variants []string `json:"variants,omitempty"`
+	// Issue is the issue as golangci-lint reported it, with the local path
+	// of its file, for client extensions.
+	Issue *Issue `json:"issue,omitempty"`
+}
+
+// decodeData returns the data of a diagnostic sent back by the client. It
+// reports false for diagnostics not published by this server.
+func decodeData(d *Diagnostic) (*diagnosticData, bool) {
+	b, err := json.Marshal(d.Data)
+	if err != nil {
+		return nil, false
+	}
+
+	var data diagnosticData
+	if err := json.Unmarshal(b, &data); err != nil || data.Linter == "" {
+		return nil, false
+	}
+
+	return &data, true
+}
+
+// diagnosticBuckets converts issues to diagnostics as they are decoded,
+// grouped by the normalized path of the file they belong to, so that large
+// outputs are not converted as a whole once decoded.
+type diagnosticBuckets struct {
+	c          *config
+	mu         sync.Mutex
+	taken      bool
+	files      map[string][]Diagnostic
+	normalized map[string]string
+	lines      fileLines
+}
+
+func newDiagnosticBuckets(c *config) *diagnosticBuckets {
+	return &diagnosticBuckets{
+		c:          c,
+		files:      make(map[string][]Diagnostic),
+		normalized: make(map[string]string),
+		lines:      make(fileLines),
+	}
+}
+
+// add converts issue to a diagnostic of its file, unless the config drops it.
+func (b *diagnosticBuckets) add(issue Issue) {
+	c := b.c
+
+	p, ok := b.normalized[issue.Pos.Filename]
+	if !ok {
+		p = normalizePath(c.issuePath(issue.Pos.Filename))
+		b.normalized[issue.Pos.Filename] = p
+	}
+
+	// issues in the module cache or in dependencies have no place here
+	if !c.linterAllowed(issue.FromLinter) || len(c.roots) > 0 && !c.inWorkspace(p) {
+		return
+	}
+
+	severity, ok := c.applyRules(&issue, c.testedSeverity(p, c.severity(issue.FromLinter)))
+	if !ok || !c.severe(severity) {
+		return
+	}
+
+	raw := issue
+	raw.Pos.Filename = c.issuePath(issue.Pos.Filename)
+	data := &diagnosticData{Linter: issue.FromLinter, Text: issue.Text, Issue: &raw}
+
+	if c.goplsDuplicate(issue.FromLinter) {
+		if c.gopls.Dedup == goplsDrop {
+			return
+		}
+
+		data.DuplicateOf = "gopls"
+	}
+
+	d := Diagnostic{
+		Range:    issueRange(&issue),
+		Severity: severity,
+		Code:     checkID(&issue),
+		Source:   &issue.FromLinter,
+		Message:  c.message(&issue),
+		Data:     data,
+	}
+
+	if len(issue.Variants) > 0 {
+		d.Message += " [" + strings.Join(issue.Variants, ", ") + "]"
+		data.Variants = issue.Variants
+	}
+
+	if fix, preview, ok := formatHunk(&issue, p, b.lines); ok {
+		d.Range = fix.Range
+		d.Message += "\n\n" + preview
+		data.Fix = []fileFix{{Edits: []TextEdit{*fix}}}
+	}
+
+	b.files[p] = append(b.files[p], d)
+}
+
+// diagnostics returns the diagnostics of every file, duplicates merged.
+func (b *diagnosticBuckets) diagnostics() map[string][]Diagnostic {
+	for p, diagnostics := range b.files {
+		b.files[p] = b.c.mergeDuplicates(diagnostics)
+	}
+
+	return b.files
+}
+
+// take returns the diagnostics converted for c. As the diagnostics are handed
+// over, only the first caller gets them.
+func (b *diagnosticBuckets) take(c *config) (map[string][]Diagnostic, bool) {
+	if b == nil || b.c != c {
+		return nil, false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.taken {
+		return nil, false
+	}
+
+	b.taken = true
+
+	return b.diagnostics(), true
+}
+
+// diagnosticsByFile returns the diagnostics of the issues of result, grouped by
+// the normalized path of the file they belong to. The diagnostics converted
+// while decoding the result are used the first time they are asked for c.
+func (c *config) diagnosticsByFile(result *GolangCILintResult) map[string][]Diagnostic {
+	if files, ok := result.buckets.take(c); ok {
+		return files
+	}
+
+	b := newDiagnosticBuckets(c)
+	for _, issue := range result.Issues {
+		b.add(issue)
+	}
+
+	return b.diagnostics()
+}
+
+// defaultMaxTrackedFiles bounds the number of files whose diagnostics are
+// remembered.
+const defaultMaxTrackedFiles = 10000
+
+// remember records the diagnostics of the latest run in dir, which covers all
+// the files golangci-lint analyzed there. Once more than limit files are known,
+// the diagnostics of the files closed the longest time ago are dropped.
+func (h *langHandler) remember(dir string, files map[string][]Diagnostic, limit int) {
+	open := make(map[string]bool)
+	for _, uri := range h.openDocuments() {
+		open[normalizePath(uriToPath(string(uri)))] = true
+	}
+
+	h.knownMu.Lock()
+	defer h.knownMu.Unlock()
+
+	// files is copied as evicting entries must not affect the caller
+	known := make(map[string][]Diagnostic, len(files))
+	for f, diagnostics := range files {
+		known[f] = diagnostics
+	}
+
+	h.known[dir] = known
+
+	type candidate struct {
+		dir, file string
+		used      time.Time
+	}
+
+	var (
+		candidates []candidate
+		total      int
+	)
+
+	for d, fs := range h.known {
+		for f, diagnostics := range fs {
+			if diagnostics == nil {
+				continue
+			}
+
+			total++
+
+			if !open[f] {
+				candidates = append(candidates, candidate{d, f, h.lastUsed[f]})
+			}
+		}
+	}
+
+	if total <= limit {
+		return
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].used.Before(candidates[j].used)
+	})
+
+	for _, c := range candidates[:minInt(total-limit, len(candidates))] {
+		// a nil entry tells an evicted file apart from one without issues
+		h.known[c.dir][c.file] = nil
+		delete(h.lastUsed, c.file)
+	}
+}
+
+// touch records that uri was in use until now.
+func (h *langHandler) touch(uri DocumentURI) {
+	h.knownMu.Lock()
+	defer h.knownMu.Unlock()
+
+	h.lastUsed[normalizePath(uriToPath(string(uri)))] = time.Now()
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+// knownDiagnostics returns the diagnostics for uri found by earlier runs.
+func (h *langHandler) knownDiagnostics(uri DocumentURI) ([]Diagnostic, bool) {
+	filename := uriToPath(string(uri))
+	cfg := h.config().forFile(filename)
+
+	h.knownMu.Lock()
+	files, ok := h.known[cfg.workingDir()]
+	h.knownMu.Unlock()
+
+	if !ok || h.excluded(cfg, uri) {
+		return nil, false
+	}
+
+	diagnostics, ok := files[normalizePath(filename)]
+	switch {
+	case !ok:
+		// the file was analyzed and had no issues
+		diagnostics = []Diagnostic{}
+	case diagnostics == nil:
+		// the diagnostics were evicted
+		return nil, false
+	}
+
+	return diagnostics, true
+}
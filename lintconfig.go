@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+const (
+	completionKindModule     = 9
+	completionKindProperty   = 10
+	completionKindValue      = 12
+	completionKindEnumMember = 20
+
+	completionTagDeprecated = 1
+)
+
+var configVersion2Pattern = regexp.MustCompile(`(?m)^version:\s*["']?2`)
+
+// isLintConfig reports whether uri is a golangci-lint YAML configuration.
+func isLintConfig(uri DocumentURI) bool {
+	base := filepath.Base(uriToPath(string(uri)))
+
+	for _, name := range configNames[:2] {
+		if base == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lintConfigSchema returns the description of the golangci-lint configuration
+// in text, for the format it declares or else for the detected golangci-lint
+// version.
+func (c *config) lintConfigSchema(text string) (*configKey, bool) {
+	v2 := configVersion2Pattern.MatchString(text) ||
+		!strings.Contains(text, "version:") && c.version != "" && compareVersions(c.version, "2.0.0") >= 0
+	if v2 {
+		return &lintConfigV2, true
+	}
+
+	return &lintConfigV1, false
+}
+
+// yamlPosition is where a position stands in a YAML document, as far as a
+// line-based reading tells.
+type yamlPosition struct {
+	// path holds the keys from the root to the mapping containing the
+	// position, "-" stepping into sequence items.
+	path []string
+	// key is set for the positions in the value of a key.
+	key string
+	// item is set for the positions in a scalar sequence item.
+	item bool
+}
+
+// yamlLine splits line into the indentation of its content, whether it is a
+// sequence item, its key if any and the text following it.
+func yamlLine(line string) (indent int, item bool, key, rest string) {
+	t := strings.TrimLeft(line, " ")
+	indent = len(line) - len(t)
+
+	if t == "-" || strings.HasPrefix(t, "- ") {
+		item = true
+		trimmed := strings.TrimLeft(strings.TrimPrefix(t, "-"), " ")
+		indent += len(t) - len(trimmed)
+		t = trimmed
+	}
+
+	if i := strings.Index(t, ":"); i > 0 && (i+1 == len(t) || t[i+1] == ' ') && t[0] != '#' {
+		return indent, item, strings.TrimSpace(t[:i]), strings.TrimSpace(t[i+1:])
+	}
+
+	return indent, item, "", t
+}
+
+// locateYAML reads where the character offset of line stands in text.
+// Positions outside of text stand nowhere.
+func locateYAML(text string, line, offset int) yamlPosition {
+	lines := strings.Split(text, "\n")
+	if line < 0 || line >= len(lines) || offset < 0 || offset > len(lines[line]) {
+		return yamlPosition{}
+	}
+
+	cur := lines[line][:offset]
+	indent, item, key, _ := yamlLine(cur)
+
+	pos := yamlPosition{key: key}
+
+	if key == "" && item {
+		// the indent of a scalar item is that of its dash
+		pos.item = true
+		indent = len(cur) - len(strings.TrimLeft(cur, " "))
+	}
+
+	// for keys of a sequence item, the item itself is a parent
+	limit, sameIndent := indent, pos.item
+	if item && !pos.item {
+		pos.path = append(pos.path, "-")
+		limit = len(cur) - len(strings.TrimLeft(cur, " "))
+		sameIndent = true
+	}
+
+	for i := line - 1; i >= 0 && (limit > 0 || sameIndent); i-- {
+		t := strings.TrimSpace(lines[i])
+		if t == "" || t[0] == '#' {
+			continue
+		}
+
+		ind, isItem, k, v := yamlLine(lines[i])
+		dash := len(lines[i]) - len(strings.TrimLeft(lines[i], " "))
+
+		if isItem {
+			if dash >= limit {
+				continue
+			}
+
+			if k != "" && ind < limit {
+				pos.path = append(pos.path, k)
+			}
+
+			pos.path = append(pos.path, "-")
+			limit, sameIndent = dash, true
+
+			continue
+		}
+
+		if k != "" && (ind < limit || sameIndent && ind == limit && v == "") {
+			pos.path = append(pos.path, k)
+			limit, sameIndent = ind, false
+		}
+	}
+
+	for i, j := 0, len(pos.path)-1; i < j; i, j = i+1, j-1 {
+		pos.path[i], pos.path[j] = pos.path[j], pos.path[i]
+	}
+
+	return pos
+}
+
+// byteOffset converts an offset in UTF-16 code units in line to a byte
+// offset.
+func byteOffset(line string, offset int) int {
+	n := 0
+
+	for i, r := range line {
+		if n >= offset {
+			return i
+		}
+
+		n += len(utf16.Encode([]rune{r}))
+	}
+
+	return len(line)
+}
+
+// lineAt returns line n of text, or "".
+func lineAt(text string, n int) string {
+	lines := strings.Split(text, "\n")
+	if n < 0 || n >= len(lines) {
+		return ""
+	}
+
+	return lines[n]
+}
+
+func (h *langHandler) handleTextDocumentCompletion(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (result interface{}, err error) {
+	var params TextDocumentPositionParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, err
+	}
+
+	items := make([]CompletionItem, 0)
+
+	text, ok := h.documentText(params.TextDocument.URI)
+	if !ok || !isLintConfig(params.TextDocument.URI) {
+		return items, nil
+	}
+
+	cfg := h.config()
+	root, v2 := cfg.lintConfigSchema(text)
+
+	line := lineAt(text, params.Position.Line)
+	pos := locateYAML(text, params.Position.Line, byteOffset(line, params.Position.Character))
+
+	parent := root.lookup(pos.path)
+	if parent == nil {
+		return items, nil
+	}
+
+	switch {
+	case pos.item && parent.list == listNone && parent.item != nil:
+		// the first key of a mapping item
+		for i := range parent.item {
+			items = append(items, keyCompletion(&parent.item[i]))
+		}
+	case pos.item:
+		items = completeList(parent.list, cfg.version, v2)
+	case pos.key != "":
+		if k := parent.child(pos.key); k != nil {
+			for _, v := range k.values {
+				items = append(items, CompletionItem{Label: v, Kind: completionKindValue})
+			}
+		}
+	case parent.settings != listNone:
+		items = completeList(parent.settings, cfg.version, v2)
+	default:
+		for i := range parent.children {
+			k := &parent.children[i]
+			items = append(items, keyCompletion(k))
+		}
+	}
+
+	return items, nil
+}
+
+// completeList returns the names that the items of a list of kind may take.
+func completeList(kind listKind, version string, v2 bool) []CompletionItem {
+	items := make([]CompletionItem, 0)
+
+	switch kind {
+	case listPresets:
+		for _, p := range lintPresets {
+			items = append(items, CompletionItem{Label: p, Kind: completionKindEnumMember})
+		}
+	case listLinters, listFormatters:
+		for i := range lintLinters {
+			l := &lintLinters[i]
+			if !l.available(version, v2, kind == listFormatters) {
+				continue
+			}
+
+			item := CompletionItem{
+				Label:         l.name,
+				Kind:          completionKindModule,
+				Documentation: &MarkupContent{Kind: "markdown", Value: l.markdown(v2)},
+			}
+
+			if l.deprecated != "" {
+				item.Tags = []int{completionTagDeprecated}
+			}
+
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func keyCompletion(k *configKey) CompletionItem {
+	item := CompletionItem{
+		Label:         k.name,
+		Kind:          completionKindProperty,
+		Documentation: &MarkupContent{Kind: "markdown", Value: k.markdown()},
+	}
+
+	if k.deprecated != "" {
+		item.Tags = []int{completionTagDeprecated}
+	}
+
+	return item
+}
+
+// markdown documents l for golangci-lint v2 or v1.
+func (l *lintLinter) markdown(v2 bool) string {
+	var b strings.Builder
+
+	b.WriteString(l.doc)
+
+	if !v2 && len(l.presets) > 0 {
+		fmt.Fprintf(&b, "\n\nPresets: %s.", strings.Join(l.presets, ", "))
+	}
+
+	switch {
+	case l.deprecated != "" && l.replacement != "":
+		fmt.Fprintf(&b, "\n\n**Deprecated** since v%s: use %s.", l.deprecated, l.replacement)
+	case l.deprecated != "":
+		fmt.Fprintf(&b, "\n\n**Deprecated** since v%s.", l.deprecated)
+	case l.v1Only && l.replacement != "":
+		fmt.Fprintf(&b, "\n\nMerged into %s in golangci-lint v2.", l.replacement)
+	case l.v1Only:
+		b.WriteString("\n\nNot a linter in golangci-lint v2.")
+	case l.formatter && !v2:
+		b.WriteString("\n\nA formatter in golangci-lint v2.")
+	}
+
+	return b.String()
+}
+
+// markdown documents k.
+func (k *configKey) markdown() string {
+	var b strings.Builder
+
+	b.WriteString(k.doc)
+
+	if k.def != "" {
+		fmt.Fprintf(&b, "\n\nDefault: `%s`.", k.def)
+	}
+
+	if len(k.values) > 0 {
+		fmt.Fprintf(&b, "\n\nValues: `%s`.", strings.Join(k.values, "`, `"))
+	}
+
+	if k.deprecated != "" {
+		fmt.Fprintf(&b, "\n\n**Deprecated**: use `%s`.", k.deprecated)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLocateYAML(t *testing.T) {
+	text := strings.Join([]string{
+		"linters:",           // 0
+		"  enable:",          // 1
+		"    - errcheck",     // 2
+		"    - gosec",        // 3
+		"  disable-all: tru", // 4
+		"",                   // 5
+		"# comment",          // 6
+		"linters-settings:",  // 7
+		"  govet:",           // 8
+		"    enable:",        // 9
+		"      - shadow",     // 10
+		"issues:",            // 11
+		"  exclude-rules:",   // 12
+		"    - linters:",     // 13
+		"        - gosec",    // 14
+		"      text: G104",   // 15
+		"  ",                 // 16
+	}, "\n")
+
+	tests := []struct {
+		name         string
+		line, offset int
+		want         yamlPosition
+	}{
+		{name: "top level key", line: 0, offset: 3, want: yamlPosition{}},
+		{name: "top level value", line: 0, offset: 8, want: yamlPosition{key: "linters"}},
+		{name: "nested key", line: 1, offset: 9, want: yamlPosition{path: []string{"linters"}, key: "enable"}},
+		{name: "scalar item", line: 3, offset: 9, want: yamlPosition{path: []string{"linters", "enable"}, item: true}},
+		{name: "value after items", line: 4, offset: 18, want: yamlPosition{path: []string{"linters"}, key: "disable-all"}},
+		{name: "after comment", line: 8, offset: 8, want: yamlPosition{path: []string{"linters-settings"}, key: "govet"}},
+		{name: "deep item", line: 10, offset: 10, want: yamlPosition{path: []string{"linters-settings", "govet", "enable"}, item: true}},
+		{name: "key of a sequence item", line: 13, offset: 14, want: yamlPosition{path: []string{"issues", "exclude-rules", "-"}, key: "linters"}},
+		{name: "item in a sequence item", line: 14, offset: 14, want: yamlPosition{path: []string{"issues", "exclude-rules", "-", "linters"}, item: true}},
+		{name: "second key of a sequence item", line: 15, offset: 14, want: yamlPosition{path: []string{"issues", "exclude-rules", "-"}, key: "text"}},
+		{name: "blank line", line: 16, offset: 2, want: yamlPosition{path: []string{"issues"}}},
+		{name: "past the last line", line: 17, offset: 0, want: yamlPosition{}},
+		{name: "negative line", line: -1, offset: 0, want: yamlPosition{}},
+		{name: "end of line", line: 2, offset: 14, want: yamlPosition{path: []string{"linters", "enable"}, item: true}},
+		{name: "past the end of line", line: 2, offset: 15, want: yamlPosition{}},
+		{name: "negative offset", line: 2, offset: -1, want: yamlPosition{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := locateYAML(text, tt.line, tt.offset); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("locateYAML(%d, %d) = %+v, want %+v", tt.line, tt.offset, got, tt.want)
+			}
+		})
+	}
+}
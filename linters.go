@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// LinterInfo describes a linter in the golangci-lint/linters response.
+type LinterInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Fast        bool   `json:"fast,omitempty"`
+	AutoFix     bool   `json:"autoFix,omitempty"`
+}
+
+// LintersResult is the response of golangci-lint/linters, listing the
+// linters the configuration enables and disables.
+type LintersResult struct {
+	Enabled  []LinterInfo `json:"enabled"`
+	Disabled []LinterInfo `json:"disabled"`
+}
+
+// SetLintersParams are the params of golangci-lint/setLinters, toggling
+// linters for the rest of the session. Reset drops the previous toggles
+// first.
+type SetLintersParams struct {
+	Enable  []string `json:"enable,omitempty"`
+	Disable []string `json:"disable,omitempty"`
+	Reset   bool     `json:"reset,omitempty"`
+}
+
+// linterLinePattern matches the linters listed by golangci-lint linters, as
+// in "errcheck: Errcheck is a program ... [fast: false, auto-fix: false]".
+var linterLinePattern = regexp.MustCompile(`^([\w-]+)(?: \([^)]*\))?: (.*?)(?: \[([^\]]*)\])?$`)
+
+func (h *langHandler) handleLinters(ctx context.Context, _ *jsonrpc2.Conn, _ *jsonrpc2.Request) (result interface{}, err error) {
+	cfg := h.config()
+	if cfg.backendName != defaultBackend || cfg.fallback != "" {
+		return nil, errNoLinterList
+	}
+
+	return h.listLinters(ctx, cfg)
+}
+
+// listLinters returns the linters golangci-lint enables and disables with
+// the configuration of cfg.
+func (h *langHandler) listLinters(ctx context.Context, cfg *config) (LintersResult, error) {
+	command := append(append(append([]string{}, cfg.binaryCommand()...), "linters"), cfg.linterFlags()...)
+
+	// golangci-lint v2 can describe the linters in JSON
+	if out, err := h.output(ctx, cfg, append(command, "--json")); err == nil {
+		var linters LintersResult
+		if err := json.Unmarshal(out, &linters); err == nil && len(linters.Enabled)+len(linters.Disabled) > 0 {
+			return normalizeLinters(linters), nil
+		}
+	}
+
+	out, err := h.output(ctx, cfg, command)
+	if err != nil {
+		return LintersResult{}, err
+	}
+
+	return parseLinters(out), nil
+}
+
+func (h *langHandler) handleSetLinters(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (result interface{}, err error) {
+	var params SetLintersParams
+	if req.Params != nil {
+		if err := json.Unmarshal(*req.Params, &params); err != nil {
+			return nil, err
+		}
+	}
+
+	h.updateConfig(func(c *config) {
+		toggles := make(map[string]bool)
+
+		if !params.Reset {
+			for linter, enabled := range c.linterToggles {
+				toggles[linter] = enabled
+			}
+		}
+
+		for _, linter := range params.Enable {
+			toggles[linter] = true
+		}
+
+		for _, linter := range params.Disable {
+			toggles[linter] = false
+		}
+
+		c.linterToggles = toggles
+	})
+
+	h.logger.Printf("golangci-lint-langserver: linters toggled for the session: %v", h.config().linterToggles)
+
+	h.refreshDiagnostics(h.config())
+
+	// the toggles are part of the command, hence of the cache keys
+	if !h.config().triggers.manualOnly() {
+		for _, uri := range h.openDocuments() {
+			h.enqueue(uri, priorityBackground)
+		}
+	}
+
+	return nil, nil
+}
+
+// normalizeLinters makes the lists of linters empty rather than null.
+func normalizeLinters(linters LintersResult) LintersResult {
+	if linters.Enabled == nil {
+		linters.Enabled = []LinterInfo{}
+	}
+
+	if linters.Disabled == nil {
+		linters.Disabled = []LinterInfo{}
+	}
+
+	return linters
+}
+
+// parseLinters parses the text output of golangci-lint linters.
+func parseLinters(out []byte) LintersResult {
+	var (
+		linters LintersResult
+		section *[]LinterInfo
+	)
+
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(line, "Enabled"):
+			section = &linters.Enabled
+
+			continue
+		case strings.HasPrefix(line, "Disabled"):
+			section = &linters.Disabled
+
+			continue
+		}
+
+		m := linterLinePattern.FindStringSubmatch(line)
+		if m == nil || section == nil {
+			continue
+		}
+
+		info := LinterInfo{Name: m[1], Description: m[2]}
+
+		for _, attr := range strings.Split(m[3], ",") {
+			switch strings.TrimSpace(attr) {
+			case "fast", "fast: true":
+				info.Fast = true
+			case "auto-fix", "auto-fix: true":
+				info.AutoFix = true
+			}
+		}
+
+		*section = append(*section, info)
+	}
+
+	return normalizeLinters(linters)
+}
+
+// output runs command in the working directory of cfg and returns its
+// standard output.
+func (h *langHandler) output(ctx context.Context, cfg *config, command []string) ([]byte, error) {
+	if cfg.fixtures != nil {
+		return nil, errNoFixture
+	}
+
+	command, err := cfg.sandboxed(cfg.executorCommand(command))
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint:gosec
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Dir = cfg.workingDir()
+	cmd.Env = cfg.commandEnv()
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %s", strings.Join(command, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
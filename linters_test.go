@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLinters(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want LintersResult
+	}{
+		{
+			name: "empty",
+			want: LintersResult{Enabled: []LinterInfo{}, Disabled: []LinterInfo{}},
+		},
+		{
+			name: "v1",
+			out: "Enabled by your configuration linters:\n" +
+				"errcheck: errcheck is a program for checking for unchecked errors in Go code. [fast: false, auto-fix: false]\n" +
+				"gosimple (megacheck): Linter for Go source code that specializes in simplifying code [fast: false, auto-fix: false]\n" +
+				"\n" +
+				"Disabled by your configuration linters:\n" +
+				"gofmt: Gofmt checks whether code was gofmt-ed. [fast: true, auto-fix: true]\n",
+			want: LintersResult{
+				Enabled: []LinterInfo{
+					{Name: "errcheck", Description: "errcheck is a program for checking for unchecked errors in Go code."},
+					{Name: "gosimple", Description: "Linter for Go source code that specializes in simplifying code"},
+				},
+				Disabled: []LinterInfo{
+					{Name: "gofmt", Description: "Gofmt checks whether code was gofmt-ed.", Fast: true, AutoFix: true},
+				},
+			},
+		},
+		{
+			name: "v2",
+			out: "Enabled by your configuration linters:\n" +
+				"errcheck: Errcheck is a program for checking for unchecked errors in Go code.\n" +
+				"Disabled by your configuration linters:\n" +
+				"godot: Check if comments end in a period. [fast, auto-fix]\n",
+			want: LintersResult{
+				Enabled: []LinterInfo{
+					{Name: "errcheck", Description: "Errcheck is a program for checking for unchecked errors in Go code."},
+				},
+				Disabled: []LinterInfo{
+					{Name: "godot", Description: "Check if comments end in a period.", Fast: true, AutoFix: true},
+				},
+			},
+		},
+		{
+			name: "lines before the sections",
+			out:  "level=warning msg=\"deprecated\"\nEnabled by your configuration linters:\nunused: Checks Go code for unused constants.\n",
+			want: LintersResult{
+				Enabled:  []LinterInfo{{Name: "unused", Description: "Checks Go code for unused constants."}},
+				Disabled: []LinterInfo{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseLinters([]byte(tt.out)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLinters() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputExecutor(t *testing.T) {
+	if _, err := exec.LookPath("echo"); err != nil {
+		t.Skip("no echo command")
+	}
+
+	// the container runtime prints the command line it is given
+	cfg := &config{
+		executor: newContainerExecutor(ContainerOptions{Runtime: "echo", Image: "golang", Workdir: "/src"}, ""),
+		env:      map[string]string{"GOFLAGS": "-mod=mod"},
+		gogc:     "50",
+	}
+
+	out, err := (&langHandler{}).output(context.Background(), cfg, []string{"go", "list", "./..."})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "run --rm -i -w /src golang env GOGC=50 GOFLAGS=-mod=mod go list ./..."
+	if got := strings.TrimSpace(string(out)); got != want {
+		t.Errorf("output() ran %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,386 @@
+package main
+
+import "strings"
+
+// lintLinter describes a golangci-lint linter for the completion and hover of
+// the golangci-lint configuration file.
+type lintLinter struct {
+	name string
+	doc  string
+	// presets are the golangci-lint v1 presets including the linter.
+	presets []string
+	// since is the first golangci-lint version providing the linter, when
+	// recent.
+	since string
+	// deprecated is the golangci-lint version deprecating the linter in
+	// favor of replacement. Deprecated linters are gone in v2.
+	deprecated  string
+	replacement string
+	// v1Only is set for the linters that golangci-lint v2 merged into
+	// replacement or dropped.
+	v1Only bool
+	// formatter is set for the linters that are formatters in v2.
+	formatter bool
+}
+
+// available reports whether golangci-lint version provides l, as a linter or
+// as a formatter in v2 depending on formatters.
+func (l *lintLinter) available(version string, v2, formatters bool) bool {
+	if l.since != "" && version != "" && compareVersions(version, l.since) < 0 {
+		return false
+	}
+
+	if !v2 {
+		return !formatters && !strings.HasPrefix(l.since, "2.")
+	}
+
+	return l.deprecated == "" && !l.v1Only && l.formatter == formatters
+}
+
+//nolint:lll
+var lintLinters = []lintLinter{
+	{name: "asasalint", doc: "Checks for passing []any as any in variadic func(...any).", presets: []string{"bugs"}},
+	{name: "asciicheck", doc: "Checks that code identifiers do not contain non-ASCII symbols.", presets: []string{"bugs", "style"}},
+	{name: "bidichk", doc: "Checks for dangerous unicode character sequences.", presets: []string{"bugs"}},
+	{name: "bodyclose", doc: "Checks whether HTTP response bodies are closed successfully.", presets: []string{"performance", "bugs"}},
+	{name: "canonicalheader", doc: "Checks whether net/http.Header uses canonical header names.", presets: []string{"style"}, since: "1.58.0"},
+	{name: "containedctx", doc: "Detects structs containing a context.Context field.", presets: []string{"style"}},
+	{name: "contextcheck", doc: "Checks whether functions use a non-inherited context.", presets: []string{"bugs"}},
+	{name: "copyloopvar", doc: "Detects places where loop variables are copied, which is unneeded since Go 1.22.", presets: []string{"style"}, since: "1.57.0"},
+	{name: "cyclop", doc: "Checks function and package cyclomatic complexity.", presets: []string{"complexity"}},
+	{name: "deadcode", doc: "Finds unused code.", presets: []string{"unused"}, deprecated: "1.49.0", replacement: "unused"},
+	{name: "decorder", doc: "Checks the declaration order and count of types, constants, variables and functions.", presets: []string{"format", "style"}},
+	{name: "depguard", doc: "Checks that package imports are in a list of acceptable packages.", presets: []string{"style", "import", "module"}},
+	{name: "dogsled", doc: "Checks assignments with too many blank identifiers.", presets: []string{"style"}},
+	{name: "dupl", doc: "Detects duplicate fragments of code.", presets: []string{"style"}},
+	{name: "dupword", doc: "Checks for duplicate words in the source code.", presets: []string{"comment"}},
+	{name: "durationcheck", doc: "Checks for two durations multiplied together.", presets: []string{"bugs"}},
+	{name: "err113", doc: "Checks the errors handling expressions.", presets: []string{"style", "error"}, since: "1.58.0"},
+	{name: "errcheck", doc: "Checks for unchecked errors. Enabled by default.", presets: []string{"bugs", "error"}},
+	{name: "errchkjson", doc: "Checks the types passed to the JSON encoding functions.", presets: []string{"bugs"}},
+	{name: "errname", doc: "Checks that sentinel errors are prefixed with Err and error types are suffixed with Error.", presets: []string{"style"}},
+	{name: "errorlint", doc: "Finds code that breaks the error wrapping scheme introduced in Go 1.13.", presets: []string{"bugs", "error"}},
+	{name: "exhaustive", doc: "Checks the exhaustiveness of enum switch statements.", presets: []string{"bugs"}},
+	{name: "exhaustivestruct", doc: "Checks that all struct fields are initialized.", presets: []string{"style", "test"}, deprecated: "1.46.0", replacement: "exhaustruct"},
+	{name: "exhaustruct", doc: "Checks that all struct fields are initialized.", presets: []string{"style", "test"}},
+	{name: "exportloopref", doc: "Checks for pointers to enclosing loop variables.", presets: []string{"bugs"}, deprecated: "1.60.2", replacement: "copyloopvar"},
+	{name: "exptostd", doc: "Detects functions from golang.org/x/exp that can be replaced by the standard library.", presets: []string{"style"}, since: "1.63.0"},
+	{name: "fatcontext", doc: "Detects nested contexts in loops and function literals.", presets: []string{"performance"}, since: "1.58.0"},
+	{name: "forbidigo", doc: "Forbids identifiers.", presets: []string{"style"}},
+	{name: "forcetypeassert", doc: "Finds forced type assertions.", presets: []string{"style"}},
+	{name: "funlen", doc: "Detects long functions.", presets: []string{"complexity"}},
+	{name: "gci", doc: "Controls the order of package imports, making it deterministic.", presets: []string{"format", "import"}, formatter: true},
+	{name: "ginkgolinter", doc: "Enforces standards of using ginkgo and gomega.", presets: []string{"style"}},
+	{name: "gocheckcompilerdirectives", doc: "Checks that go compiler directive comments (//go:) are valid.", presets: []string{"bugs"}},
+	{name: "gochecknoglobals", doc: "Checks that no global variables exist.", presets: []string{"style"}},
+	{name: "gochecknoinits", doc: "Checks that no init functions are present.", presets: []string{"style"}},
+	{name: "gochecksumtype", doc: "Runs exhaustiveness checks on Go sum types.", presets: []string{"bugs"}},
+	{name: "gocognit", doc: "Computes and checks the cognitive complexity of functions.", presets: []string{"complexity"}},
+	{name: "goconst", doc: "Finds repeated strings that could be replaced by a constant.", presets: []string{"style"}},
+	{name: "gocritic", doc: "Provides diagnostics that check for bugs, performance and style issues.", presets: []string{"style", "metalinter"}},
+	{name: "gocyclo", doc: "Computes and checks the cyclomatic complexity of functions.", presets: []string{"complexity"}},
+	{name: "godot", doc: "Checks that comments end in a period.", presets: []string{"style", "comment"}},
+	{name: "godox", doc: "Detects usage of FIXME, TODO and other keywords inside comments.", presets: []string{"style", "comment"}},
+	{name: "goerr113", doc: "Checks the errors handling expressions.", presets: []string{"style", "error"}, deprecated: "1.58.0", replacement: "err113"},
+	{name: "gofmt", doc: "Checks that the code was formatted with gofmt.", presets: []string{"format"}, formatter: true},
+	{name: "gofumpt", doc: "Checks that the code was formatted with gofumpt.", presets: []string{"format"}, formatter: true},
+	{name: "goheader", doc: "Checks that file headers match a pattern.", presets: []string{"style"}},
+	{name: "goimports", doc: "Checks that imports were formatted with goimports.", presets: []string{"format", "import"}, formatter: true},
+	{name: "golines", doc: "Shortens long lines.", since: "2.0.0", formatter: true},
+	{name: "golint", doc: "Prints out style mistakes.", presets: []string{"style"}, deprecated: "1.41.0", replacement: "revive"},
+	{name: "gomnd", doc: "Detects magic numbers.", presets: []string{"style"}, deprecated: "1.58.0", replacement: "mnd"},
+	{name: "gomoddirectives", doc: "Manages the use of replace, retract and excludes directives in go.mod.", presets: []string{"style", "module"}},
+	{name: "gomodguard", doc: "Allow and block lists of direct Go module dependencies.", presets: []string{"style", "import", "module"}},
+	{name: "goprintffuncname", doc: "Checks that printf-like functions are named with f at the end.", presets: []string{"style"}},
+	{name: "gosec", doc: "Inspects source code for security problems.", presets: []string{"bugs"}},
+	{name: "gosimple", doc: "Specializes in simplifying code. Enabled by default.", presets: []string{"style"}, v1Only: true, replacement: "staticcheck"},
+	{name: "gosmopolitan", doc: "Reports certain i18n/l10n anti-patterns.", presets: []string{"bugs"}},
+	{name: "govet", doc: "Examines Go source code and reports suspicious constructs, like go vet. Enabled by default.", presets: []string{"bugs", "metalinter"}},
+	{name: "grouper", doc: "Analyzes expression groups.", presets: []string{"style"}},
+	{name: "iface", doc: "Detects the incorrect use of interfaces.", presets: []string{"style"}, since: "1.62.0"},
+	{name: "ifshort", doc: "Checks that variables used only in if statements are declared in them.", presets: []string{"style"}, deprecated: "1.48.0"},
+	{name: "importas", doc: "Enforces consistent import aliases.", presets: []string{"style"}},
+	{name: "inamedparam", doc: "Reports interfaces with unnamed method parameters.", presets: []string{"style"}},
+	{name: "ineffassign", doc: "Detects when assignments to existing variables are not used. Enabled by default.", presets: []string{"unused"}},
+	{name: "interfacebloat", doc: "Checks the number of methods inside an interface.", presets: []string{"style"}},
+	{name: "interfacer", doc: "Suggests narrower interface types.", presets: []string{"style"}, deprecated: "1.38.0"},
+	{name: "intrange", doc: "Finds places where for loops could make use of an integer range.", presets: []string{"style"}, since: "1.57.0"},
+	{name: "ireturn", doc: "Accept interfaces, return concrete types.", presets: []string{"style"}},
+	{name: "lll", doc: "Reports long lines.", presets: []string{"style"}},
+	{name: "loggercheck", doc: "Checks key value pairs for common logger libraries.", presets: []string{"style", "bugs"}},
+	{name: "maintidx", doc: "Measures the maintainability index of each function.", presets: []string{"complexity"}},
+	{name: "makezero", doc: "Finds slice declarations with non-zero initial length.", presets: []string{"style", "bugs"}},
+	{name: "maligned", doc: "Detects structs that would take less memory if their fields were sorted.", presets: []string{"performance"}, deprecated: "1.38.0", replacement: "govet"},
+	{name: "mirror", doc: "Reports wrong mirror patterns of bytes/strings usage.", presets: []string{"style"}},
+	{name: "misspell", doc: "Finds commonly misspelled English words.", presets: []string{"style", "comment"}},
+	{name: "mnd", doc: "Detects magic numbers.", presets: []string{"style"}, since: "1.58.0"},
+	{name: "musttag", doc: "Enforces field tags in (un)marshaled structs.", presets: []string{"style", "bugs"}},
+	{name: "nakedret", doc: "Checks that functions with naked returns are not longer than a maximum size.", presets: []string{"style"}},
+	{name: "nestif", doc: "Reports deeply nested if statements.", presets: []string{"complexity"}},
+	{name: "nilerr", doc: "Finds code returning nil even though it checks that the error is not nil.", presets: []string{"bugs"}},
+	{name: "nilnesserr", doc: "Reports code checking an error and then returning another error that is nil.", presets: []string{"bugs"}, since: "1.63.0"},
+	{name: "nilnil", doc: "Checks that there is no simultaneous return of a nil error and an invalid value.", presets: []string{"style"}},
+	{name: "nlreturn", doc: "Checks for a new line before return and branch statements.", presets: []string{"style"}},
+	{name: "noctx", doc: "Finds HTTP requests sent without a context.Context.", presets: []string{"performance", "bugs"}},
+	{name: "nolintlint", doc: "Reports ill-formed or insufficient nolint directives.", presets: []string{"style"}},
+	{name: "nonamedreturns", doc: "Reports all named returns.", presets: []string{"style"}},
+	{name: "nosnakecase", doc: "Detects snake case in identifiers.", presets: []string{"style"}, deprecated: "1.48.1", replacement: "revive"},
+	{name: "nosprintfhostport", doc: "Checks for misuse of Sprintf to construct a host with port in a URL.", presets: []string{"style"}},
+	{name: "paralleltest", doc: "Detects missing usage of t.Parallel() in tests.", presets: []string{"style", "test"}},
+	{name: "perfsprint", doc: "Checks that fmt.Sprintf can be replaced with a faster alternative.", presets: []string{"performance"}},
+	{name: "prealloc", doc: "Finds slice declarations that could potentially be pre-allocated.", presets: []string{"performance"}},
+	{name: "predeclared", doc: "Finds code shadowing one of Go's predeclared identifiers.", presets: []string{"style"}},
+	{name: "promlinter", doc: "Checks Prometheus metrics naming via promlint.", presets: []string{"style"}},
+	{name: "protogetter", doc: "Reports direct reads from proto message fields when getters should be used.", presets: []string{"bugs"}},
+	{name: "reassign", doc: "Checks that package variables are not reassigned.", presets: []string{"bugs"}},
+	{name: "recvcheck", doc: "Checks for receiver type consistency.", presets: []string{"bugs"}, since: "1.62.0"},
+	{name: "revive", doc: "Fast, configurable, extensible, flexible, and beautiful linter for Go. Drop-in replacement of golint.", presets: []string{"style", "metalinter"}},
+	{name: "rowserrcheck", doc: "Checks whether Rows.Err of database/sql is checked.", presets: []string{"bugs", "sql"}},
+	{name: "scopelint", doc: "Checks for unpinned variables in go programs.", presets: []string{"bugs"}, deprecated: "1.39.0", replacement: "exportloopref"},
+	{name: "sloglint", doc: "Ensures a consistent code style when using log/slog.", presets: []string{"style", "format"}},
+	{name: "spancheck", doc: "Checks for mistakes with OpenTelemetry/Census spans.", presets: []string{"bugs"}, since: "1.56.0"},
+	{name: "sqlclosecheck", doc: "Checks that sql.Rows, sql.Stmt, sqlx.NamedStmt and pgx.Query are closed.", presets: []string{"bugs", "sql"}},
+	{name: "staticcheck", doc: "A set of rules from staticcheck. Enabled by default.", presets: []string{"bugs", "metalinter"}},
+	{name: "structcheck", doc: "Finds unused struct fields.", presets: []string{"unused"}, deprecated: "1.49.0", replacement: "unused"},
+	{name: "stylecheck", doc: "A replacement for golint.", presets: []string{"style"}, v1Only: true, replacement: "staticcheck"},
+	{name: "swaggo", doc: "Formats swaggo comments.", since: "2.0.0", formatter: true},
+	{name: "tagalign", doc: "Checks that struct tags are well aligned.", presets: []string{"style"}},
+	{name: "tagliatelle", doc: "Checks the struct tags.", presets: []string{"style"}},
+	{name: "tenv", doc: "Detects using os.Setenv instead of t.Setenv.", presets: []string{"style"}, deprecated: "1.64.0", replacement: "usetesting"},
+	{name: "testableexamples", doc: "Checks that examples are testable, having an expected output.", presets: []string{"test"}},
+	{name: "testifylint", doc: "Checks the usage of github.com/stretchr/testify.", presets: []string{"test", "bugs"}},
+	{name: "testpackage", doc: "Makes you use a separate _test package.", presets: []string{"style", "test"}},
+	{name: "thelper", doc: "Detects test helpers without a t.Helper() call and checks the consistency of test helpers.", presets: []string{"test"}},
+	{name: "tparallel", doc: "Detects inappropriate usage of t.Parallel() in tests.", presets: []string{"style", "test"}},
+	{name: "typecheck", doc: "Parses and type-checks Go code, like the front-end of a Go compiler.", presets: []string{"bugs"}, v1Only: true},
+	{name: "unconvert", doc: "Removes unnecessary type conversions.", presets: []string{"style"}},
+	{name: "unparam", doc: "Reports unused function parameters.", presets: []string{"unused"}},
+	{name: "unused", doc: "Checks Go code for unused constants, variables, functions and types. Enabled by default.", presets: []string{"unused"}},
+	{name: "usestdlibvars", doc: "Detects the possibility to use variables and constants from the Go standard library.", presets: []string{"style"}},
+	{name: "usetesting", doc: "Reports uses of functions with a replacement inside the testing package.", presets: []string{"test"}, since: "1.63.0"},
+	{name: "varcheck", doc: "Finds unused global variables and constants.", presets: []string{"unused"}, deprecated: "1.49.0", replacement: "unused"},
+	{name: "varnamelen", doc: "Checks that the length of a variable's name matches its scope.", presets: []string{"style"}},
+	{name: "wastedassign", doc: "Finds wasted assignment statements.", presets: []string{"style"}},
+	{name: "whitespace", doc: "Checks for leading and trailing whitespace.", presets: []string{"style"}},
+	{name: "wrapcheck", doc: "Checks that errors returned from external packages are wrapped.", presets: []string{"style", "error"}},
+	{name: "wsl", doc: "Adds or removes empty lines.", presets: []string{"style"}},
+	{name: "zerologlint", doc: "Detects the wrong usage of zerolog.", presets: []string{"bugs"}},
+}
+
+// lintPresets are the golangci-lint v1 presets.
+var lintPresets = []string{
+	"bugs", "comment", "complexity", "error", "format", "import", "metalinter",
+	"module", "performance", "sql", "style", "test", "unused",
+}
+
+// listKind tells what the items of a configuration sequence name.
+type listKind int
+
+const (
+	listNone listKind = iota
+	listLinters
+	listFormatters
+	listPresets
+)
+
+// configKey describes a key of the golangci-lint configuration.
+type configKey struct {
+	name       string
+	doc        string
+	def        string
+	deprecated string
+	// values are the allowed values of the key.
+	values []string
+	// list tells what the items of a sequence value name.
+	list listKind
+	// settings is set for mappings keyed by linter or formatter names.
+	settings listKind
+	children []configKey
+	// item describes the mapping items of a sequence value.
+	item []configKey
+}
+
+// child returns the child of k named name, or nil.
+func (k *configKey) child(name string) *configKey {
+	for i := range k.children {
+		if k.children[i].name == name {
+			return &k.children[i]
+		}
+	}
+
+	return nil
+}
+
+// lookup returns the key at path below k, "-" stepping into sequence items,
+// or nil.
+func (k *configKey) lookup(path []string) *configKey {
+	for _, name := range path {
+		if name == "-" {
+			if k.item == nil {
+				return nil
+			}
+
+			k = &configKey{children: k.item}
+
+			continue
+		}
+
+		if k.settings != listNone {
+			// the settings of a linter are not described
+			return nil
+		}
+
+		if k = k.child(name); k == nil {
+			return nil
+		}
+	}
+
+	return k
+}
+
+var lintConfigV1 = configKey{children: []configKey{
+	{name: "run", doc: "Options for the analysis running.", children: []configKey{
+		{name: "timeout", doc: "Timeout for the whole run.", def: "1m"},
+		{name: "concurrency", doc: "Number of operating system threads used.", def: "the number of CPUs"},
+		{name: "issues-exit-code", doc: "Exit code when issues are found.", def: "1"},
+		{name: "tests", doc: "Whether test files are analyzed.", def: "true"},
+		{name: "build-tags", doc: "Build tags used by all linters."},
+		{name: "modules-download-mode", doc: "Value of the -mod flag of go commands.", values: []string{"readonly", "vendor", "mod"}},
+		{name: "allow-parallel-runners", doc: "Allows running several golangci-lint instances at the same time.", def: "false"},
+		{name: "allow-serial-runners", doc: "Waits for the other golangci-lint instances instead of failing.", def: "false"},
+		{name: "go", doc: "Targeted Go version.", def: "the version of go.mod"},
+		{name: "skip-dirs", doc: "Directories whose issues are not reported.", deprecated: "issues.exclude-dirs"},
+		{name: "skip-files", doc: "Files whose issues are not reported.", deprecated: "issues.exclude-files"},
+	}},
+	{name: "output", doc: "Output configuration options.", children: []configKey{
+		{name: "formats", doc: "Output formats and the files to write them to.", def: "colored-line-number"},
+		{name: "format", doc: "Output format.", def: "colored-line-number", deprecated: "output.formats"},
+		{name: "print-issued-lines", doc: "Whether the lines of code with issues are printed.", def: "true"},
+		{name: "print-linter-name", doc: "Whether the linter name is printed.", def: "true"},
+		{name: "uniq-by-line", doc: "Whether only one issue is reported per line.", def: "true"},
+		{name: "path-prefix", doc: "Prefix added to the output file references."},
+		{name: "sort-results", doc: "Whether the results are sorted.", def: "false"},
+		{name: "show-stats", doc: "Whether statistics are shown.", def: "false"},
+	}},
+	{name: "linters", doc: "Selection of the linters to run.", children: []configKey{
+		{name: "enable", doc: "Linters enabled in addition to the defaults.", list: listLinters},
+		{name: "disable", doc: "Linters disabled.", list: listLinters},
+		{name: "enable-all", doc: "Enables all linters.", def: "false"},
+		{name: "disable-all", doc: "Disables all linters, leaving only the enabled ones.", def: "false"},
+		{name: "presets", doc: "Enables the linters of the presets.", list: listPresets},
+		{name: "fast", doc: "Runs only the fast linters among the enabled ones.", def: "false"},
+	}},
+	{name: "linters-settings", doc: "Settings of the linters, keyed by linter name.", settings: listLinters},
+	{name: "issues", doc: "Filtering of the reported issues.", children: []configKey{
+		{name: "exclude", doc: "Regular expressions of issue texts to exclude."},
+		{name: "exclude-rules", doc: "Rules excluding issues by path, linter, text or source.", item: []configKey{
+			{name: "path", doc: "Regular expression of the paths of excluded issues."},
+			{name: "path-except", doc: "Regular expression of the paths whose issues are not excluded."},
+			{name: "linters", doc: "Linters whose issues are excluded.", list: listLinters},
+			{name: "text", doc: "Regular expression of the texts of excluded issues."},
+			{name: "source", doc: "Regular expression of the source lines of excluded issues."},
+		}},
+		{name: "exclude-use-default", doc: "Whether the default exclusions are applied.", def: "true"},
+		{name: "exclude-case-sensitive", doc: "Whether exclude and exclude-rules are case sensitive.", def: "false"},
+		{name: "exclude-dirs", doc: "Regular expressions of directories whose issues are excluded."},
+		{name: "exclude-dirs-use-default", doc: "Whether vendor, third_party, testdata, examples and Godeps are excluded.", def: "true"},
+		{name: "exclude-files", doc: "Regular expressions of files whose issues are excluded."},
+		{name: "exclude-generated", doc: "How generated files are detected.", def: "lax", values: []string{"lax", "strict", "disable"}},
+		{name: "include", doc: "Identifiers of the default exclusions not to apply."},
+		{name: "max-issues-per-linter", doc: "Maximum number of issues per linter, 0 for no limit.", def: "50"},
+		{name: "max-same-issues", doc: "Maximum number of issues with the same text, 0 for no limit.", def: "3"},
+		{name: "new", doc: "Shows only the issues of uncommitted changes.", def: "false"},
+		{name: "new-from-rev", doc: "Shows only the issues created after the git revision."},
+		{name: "new-from-patch", doc: "Shows only the issues of the changes in the patch file."},
+		{name: "whole-files", doc: "Shows all the issues of the files changed by new, new-from-rev or new-from-patch.", def: "false"},
+		{name: "fix", doc: "Fixes the issues found, if supported by the linter.", def: "false"},
+	}},
+	{name: "severity", doc: "Severities of the issues.", children: []configKey{
+		{name: "default-severity", doc: "Severity of the issues matching no rule."},
+		{name: "case-sensitive", doc: "Whether the rules are case sensitive.", def: "false"},
+		{name: "rules", doc: "Rules setting the severity of issues.", item: []configKey{
+			{name: "severity", doc: "Severity of the matching issues."},
+			{name: "path", doc: "Regular expression of the paths of the matching issues."},
+			{name: "linters", doc: "Linters of the matching issues.", list: listLinters},
+			{name: "text", doc: "Regular expression of the texts of the matching issues."},
+			{name: "source", doc: "Regular expression of the source lines of the matching issues."},
+		}},
+	}},
+}}
+
+// exclusionRuleKeysV2 describes the rules of linters.exclusions.rules.
+var exclusionRuleKeysV2 = []configKey{
+	{name: "path", doc: "Regular expression of the paths of excluded issues."},
+	{name: "path-except", doc: "Regular expression of the paths whose issues are not excluded."},
+	{name: "linters", doc: "Linters whose issues are excluded.", list: listLinters},
+	{name: "text", doc: "Regular expression of the texts of excluded issues."},
+	{name: "source", doc: "Regular expression of the source lines of excluded issues."},
+}
+
+var lintConfigV2 = configKey{children: []configKey{
+	{name: "version", doc: "Version of the configuration format.", values: []string{`"2"`}},
+	{name: "run", doc: "Options for the analysis running.", children: []configKey{
+		{name: "timeout", doc: "Timeout for the whole run, 0 for none.", def: "0"},
+		{name: "concurrency", doc: "Number of operating system threads used.", def: "the number of CPUs"},
+		{name: "issues-exit-code", doc: "Exit code when issues are found.", def: "1"},
+		{name: "tests", doc: "Whether test files are analyzed.", def: "true"},
+		{name: "build-tags", doc: "Build tags used by all linters."},
+		{name: "modules-download-mode", doc: "Value of the -mod flag of go commands.", values: []string{"readonly", "vendor", "mod"}},
+		{name: "allow-parallel-runners", doc: "Allows running several golangci-lint instances at the same time.", def: "false"},
+		{name: "allow-serial-runners", doc: "Waits for the other golangci-lint instances instead of failing.", def: "false"},
+		{name: "go", doc: "Targeted Go version.", def: "the version of go.mod"},
+		{name: "relative-path-mode", doc: "What the relative paths of the configuration are relative to.", def: "cfg", values: []string{"gomod", "gitroot", "cfg", "wd"}},
+	}},
+	{name: "output", doc: "Output configuration options.", children: []configKey{
+		{name: "formats", doc: "Output formats and the files to write them to.", def: "text"},
+		{name: "path-prefix", doc: "Prefix added to the output file references."},
+		{name: "path-mode", doc: "Set to abs to report absolute paths.", values: []string{"abs"}},
+		{name: "sort-order", doc: "Order of the sorted issues.", def: "file"},
+		{name: "show-stats", doc: "Whether statistics are shown.", def: "true"},
+	}},
+	{name: "linters", doc: "Selection and settings of the linters.", children: []configKey{
+		{name: "default", doc: "Linters enabled by default.", def: "standard", values: []string{"standard", "all", "none", "fast"}},
+		{name: "enable", doc: "Linters enabled in addition to the default ones.", list: listLinters},
+		{name: "disable", doc: "Linters disabled.", list: listLinters},
+		{name: "settings", doc: "Settings of the linters, keyed by linter name.", settings: listLinters},
+		{name: "exclusions", doc: "Exclusion of issues.", children: []configKey{
+			{name: "generated", doc: "How generated files are detected.", def: "strict", values: []string{"lax", "strict", "disable"}},
+			{name: "warn-unused", doc: "Warns about unused exclusion rules.", def: "false"},
+			{name: "presets", doc: "Predefined exclusion rules.", values: []string{"comments", "std-error-handling", "common-false-positives", "legacy"}},
+			{name: "rules", doc: "Rules excluding issues by path, linter, text or source.", item: exclusionRuleKeysV2},
+			{name: "paths", doc: "Regular expressions of the paths whose issues are excluded."},
+			{name: "paths-except", doc: "Regular expressions of the paths whose issues are not excluded."},
+		}},
+	}},
+	{name: "formatters", doc: "Selection and settings of the formatters.", children: []configKey{
+		{name: "enable", doc: "Formatters enabled.", list: listFormatters},
+		{name: "settings", doc: "Settings of the formatters, keyed by formatter name.", settings: listFormatters},
+		{name: "exclusions", doc: "Exclusion of files from formatting.", children: []configKey{
+			{name: "generated", doc: "How generated files are detected.", def: "lax", values: []string{"lax", "strict", "disable"}},
+			{name: "paths", doc: "Regular expressions of the paths not formatted."},
+			{name: "warn-unused", doc: "Warns about unused exclusion paths.", def: "false"},
+		}},
+	}},
+	{name: "issues", doc: "Filtering of the reported issues.", children: []configKey{
+		{name: "max-issues-per-linter", doc: "Maximum number of issues per linter, 0 for no limit.", def: "50"},
+		{name: "max-same-issues", doc: "Maximum number of issues with the same text, 0 for no limit.", def: "3"},
+		{name: "uniq-by-line", doc: "Whether only one issue is reported per line.", def: "true"},
+		{name: "new", doc: "Shows only the issues of uncommitted changes.", def: "false"},
+		{name: "new-from-rev", doc: "Shows only the issues created after the git revision."},
+		{name: "new-from-merge-base", doc: "Shows only the issues created after the merge base with the branch."},
+		{name: "new-from-patch", doc: "Shows only the issues of the changes in the patch file."},
+		{name: "whole-files", doc: "Shows all the issues of the changed files.", def: "false"},
+		{name: "fix", doc: "Fixes the issues found, if supported by the linter.", def: "false"},
+	}},
+	{name: "severity", doc: "Severities of the issues.", children: []configKey{
+		{name: "default", doc: "Severity of the issues matching no rule."},
+		{name: "rules", doc: "Rules setting the severity of issues.", item: []configKey{
+			{name: "severity", doc: "Severity of the matching issues."},
+			{name: "path", doc: "Regular expression of the paths of the matching issues."},
+			{name: "path-except", doc: "Regular expression of the paths not matched."},
+			{name: "linters", doc: "Linters of the matching issues.", list: listLinters},
+			{name: "text", doc: "Regular expression of the texts of the matching issues."},
+			{name: "source", doc: "Regular expression of the source lines of the matching issues."},
+		}},
+	}},
+}}
@@ -2,35 +2,108 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"log"
-	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 var _ logger = (*stdLogger)(nil)
 
+type logLevel int32
+
+const (
+	levelError logLevel = iota
+	levelInfo
+	levelDebug
+	levelTrace
+)
+
+var levelNames = [...]string{"error", "info", "debug", "trace"}
+
+func (l logLevel) String() string {
+	return levelNames[l]
+}
+
+func parseLogLevel(s string) (logLevel, error) {
+	for i, name := range levelNames {
+		if strings.EqualFold(s, name) {
+			return logLevel(i), nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown log level %q: want error, info, debug or trace", s)
+}
+
+// logFields are structured values attached to a log entry.
+type logFields map[string]interface{}
+
 type logger interface {
+	Errorf(format string, args ...interface{})
 	Printf(format string, args ...interface{})
+	Debugf(format string, args ...interface{})
+	// DebugJSON dumps arg as JSON at the trace level.
 	DebugJSON(label string, arg interface{})
+	// Event logs msg with structured fields.
+	Event(level logLevel, msg string, fields logFields)
+	SetLevel(level logLevel)
 }
 
+// stdLogger writes logs to w, which must never be stdout as that would
+// corrupt the LSP stream. With json set, every entry is written as a JSON
+// object on its own line.
 type stdLogger struct {
-	debug  bool
+	level int32
+	json  bool
+
+	mu     sync.Mutex
+	w      io.Writer
 	stderr *log.Logger
 }
 
-func newStdLogger(debug bool) *stdLogger {
-	return &stdLogger{
-		debug:  debug,
-		stderr: log.New(os.Stderr, "", 0),
+func newStdLogger(w io.Writer, flags int, level logLevel, json bool) *stdLogger {
+	l := &stdLogger{
+		json:   json,
+		w:      w,
+		stderr: log.New(w, "", flags),
 	}
+	l.SetLevel(level)
+
+	return l
+}
+
+func (l *stdLogger) SetLevel(level logLevel) {
+	atomic.StoreInt32(&l.level, int32(level))
+}
+
+func (l *stdLogger) enabled(level logLevel) bool {
+	return logLevel(atomic.LoadInt32(&l.level)) >= level
+}
+
+func (l *stdLogger) Errorf(format string, args ...interface{}) {
+	l.Event(levelError, fmt.Sprintf(format, args...), nil)
 }
 
 func (l *stdLogger) Printf(format string, args ...interface{}) {
-	l.stderr.Printf(format, args...)
+	l.Event(levelInfo, fmt.Sprintf(format, args...), nil)
+}
+
+func (l *stdLogger) Debugf(format string, args ...interface{}) {
+	l.Event(levelDebug, fmt.Sprintf(format, args...), nil)
 }
 
 func (l *stdLogger) DebugJSON(label string, arg interface{}) {
-	if !l.debug {
+	if !l.enabled(levelTrace) {
+		return
+	}
+
+	if l.json {
+		l.writeJSON(levelTrace, strings.TrimSuffix(label, ":"), logFields{"data": arg})
+
 		return
 	}
 
@@ -41,3 +114,53 @@ func (l *stdLogger) DebugJSON(label string, arg interface{}) {
 
 	l.stderr.Println(label, string(b))
 }
+
+func (l *stdLogger) Event(level logLevel, msg string, fields logFields) {
+	if !l.enabled(level) {
+		return
+	}
+
+	if l.json {
+		l.writeJSON(level, msg, fields)
+
+		return
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	var b strings.Builder
+
+	b.WriteString(msg)
+
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, fields[k])
+	}
+
+	l.stderr.Print(b.String())
+}
+
+func (l *stdLogger) writeJSON(level logLevel, msg string, fields logFields) {
+	entry := make(logFields, len(fields)+3)
+	for k, v := range fields {
+		entry[k] = v
+	}
+
+	entry["time"] = time.Now().Format(time.RFC3339Nano)
+	entry["level"] = level.String()
+	entry["msg"] = msg
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		b, _ = json.Marshal(logFields{"level": levelError.String(), "msg": err.Error()})
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, _ = l.w.Write(append(b, '\n'))
+}
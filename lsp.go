@@ -1,14 +1,95 @@
 package main
 
+import "encoding/json"
+
 type DocumentURI string
 
 type InitializeParams struct {
-	RootURI               string                `json:"rootUri,omitempty"`
-	InitializationOptions InitializationOptions `json:"initializationOptions,omitempty"`
+	ProcessID             *int               `json:"processId,omitempty"`
+	RootURI               string             `json:"rootUri,omitempty"`
+	RootPath              string             `json:"rootPath,omitempty"`
+	WorkspaceFolders      []WorkspaceFolder  `json:"workspaceFolders,omitempty"`
+	InitializationOptions json.RawMessage    `json:"initializationOptions,omitempty"`
+	Capabilities          ClientCapabilities `json:"capabilities,omitempty"`
 }
 
 type InitializationOptions struct {
-	Command []string
+	Backend      string            `json:"backend,omitempty"`
+	Command      []string          `json:"command"`
+	Container    *ContainerOptions `json:"container,omitempty"`
+	SSH          *SSHOptions       `json:"ssh,omitempty"`
+	Sandbox      *SandboxOptions   `json:"sandbox,omitempty"`
+	WarmCache    bool              `json:"warmCache,omitempty"`
+	PersistCache bool              `json:"persistCache,omitempty"`
+	Install      *InstallOptions   `json:"install,omitempty"`
+	Severity     map[string]string `json:"severity,omitempty"`
+	MinSeverity  string            `json:"minSeverity,omitempty"`
+	TestSeverity string            `json:"testSeverity,omitempty"`
+	Rules        []Rule            `json:"rules,omitempty"`
+
+	// CommandResolution is "goTool" to run the golangci-lint pinned by
+	// go.mod.
+	CommandResolution string `json:"commandResolution,omitempty"`
+
+	MessageTemplate string `json:"messageTemplate,omitempty"`
+	Debug           bool   `json:"debug,omitempty"`
+	LogLevel        string `json:"logLevel,omitempty"`
+
+	MaxDiagnosticsPerFile int `json:"maxDiagnosticsPerFile,omitempty"`
+	MaxDiagnostics        int `json:"maxDiagnostics,omitempty"`
+	MaxTrackedFiles       int `json:"maxTrackedFiles,omitempty"`
+
+	ExcludePaths     []string `json:"excludePaths,omitempty"`
+	IgnoreFiles      []string `json:"ignoreFiles,omitempty"`
+	IncludeGenerated bool     `json:"includeGenerated,omitempty"`
+
+	IncludeVendor     bool `json:"includeVendor,omitempty"`
+	IncludeTestdata   bool `json:"includeTestdata,omitempty"`
+	IncludeGitIgnored bool `json:"includeGitIgnored,omitempty"`
+	IncludeReplaced   bool `json:"includeReplaced,omitempty"`
+
+	EnabledLinters  []string `json:"enabledLinters,omitempty"`
+	DisabledLinters []string `json:"disabledLinters,omitempty"`
+	UnusedNolint    bool     `json:"unusedNolint,omitempty"`
+
+	Gopls      *GoplsOptions      `json:"gopls,omitempty"`
+	Duplicates *DuplicatesOptions `json:"duplicates,omitempty"`
+	Telemetry  *TelemetryOptions  `json:"telemetry,omitempty"`
+
+	Concurrency int    `json:"concurrency,omitempty"`
+	GOGC        string `json:"gogc,omitempty"`
+	CacheDir    string `json:"cacheDir,omitempty"`
+
+	AllowParallelRunners bool `json:"allowParallelRunners,omitempty"`
+
+	Env            map[string]string `json:"env,omitempty"`
+	PackagesDriver string            `json:"packagesDriver,omitempty"`
+	WorkingDir     string            `json:"workingDir,omitempty"`
+
+	GitMode     string   `json:"gitMode,omitempty"`
+	Strategy    string   `json:"strategy,omitempty"`
+	ChangeDelay int      `json:"changeDelay,omitempty"`
+	IdleDelay   int      `json:"idleDelay,omitempty"`
+	RunTriggers []string `json:"runTriggers,omitempty"`
+
+	LintWorkspaceOnStart bool  `json:"lintWorkspaceOnStart,omitempty"`
+	LintDependents       bool  `json:"lintDependents,omitempty"`
+	LintTests            *bool `json:"lintTests,omitempty"`
+
+	SkipUnchanged bool `json:"skipUnchanged,omitempty"`
+
+	CodeLens bool `json:"codeLens,omitempty"`
+	Stats    bool `json:"stats,omitempty"`
+
+	ReportFile string           `json:"reportFile,omitempty"`
+	Variants   []VariantOptions `json:"variants,omitempty"`
+
+	Folders map[string]FolderOptions `json:"folders,omitempty"`
+}
+
+type WorkspaceFolder struct {
+	URI  string `json:"uri"`
+	Name string `json:"name"`
 }
 
 type InitializeResult struct {
@@ -34,7 +115,11 @@ type TextDocumentSyncOptions struct {
 	Change            TextDocumentSyncKind `json:"change,omitempty"`
 	WillSave          bool                 `json:"willSave,omitempty"`
 	WillSaveWaitUntil bool                 `json:"willSaveWaitUntil,omitempty"`
-	Save              bool                 `json:"save,omitempty"`
+	Save              *SaveOptions         `json:"save,omitempty"`
+}
+
+type SaveOptions struct {
+	IncludeText bool `json:"includeText,omitempty"`
 }
 
 type ServerCapabilities struct {
@@ -44,7 +129,179 @@ type ServerCapabilities struct {
 	DefinitionProvider         bool                    `json:"definitionProvider,omitempty"`
 	DocumentFormattingProvider bool                    `json:"documentFormattingProvider,omitempty"`
 	HoverProvider              bool                    `json:"hoverProvider,omitempty"`
-	CodeActionProvider         bool                    `json:"codeActionProvider,omitempty"`
+	CodeActionProvider         interface{}             `json:"codeActionProvider,omitempty"`
+	ExecuteCommandProvider     *ExecuteCommandOptions  `json:"executeCommandProvider,omitempty"`
+	CodeLensProvider           *CodeLensOptions        `json:"codeLensProvider,omitempty"`
+	DiagnosticProvider         *DiagnosticOptions      `json:"diagnosticProvider,omitempty"`
+	Workspace                  *WorkspaceOptions       `json:"workspace,omitempty"`
+}
+
+type DiagnosticOptions struct {
+	Identifier            string `json:"identifier,omitempty"`
+	InterFileDependencies bool   `json:"interFileDependencies"`
+	WorkspaceDiagnostics  bool   `json:"workspaceDiagnostics"`
+}
+
+type DocumentDiagnosticParams struct {
+	TextDocument     TextDocumentIdentifier `json:"textDocument"`
+	Identifier       string                 `json:"identifier,omitempty"`
+	PreviousResultID string                 `json:"previousResultId,omitempty"`
+}
+
+type FullDocumentDiagnosticReport struct {
+	Kind  string       `json:"kind"`
+	Items []Diagnostic `json:"items"`
+}
+
+type CodeActionOptions struct {
+	ResolveProvider bool `json:"resolveProvider,omitempty"`
+}
+
+type WorkspaceOptions struct {
+	WorkspaceFolders *WorkspaceFoldersOptions `json:"workspaceFolders,omitempty"`
+	FileOperations   *FileOperationsOptions   `json:"fileOperations,omitempty"`
+}
+
+type FileOperationsOptions struct {
+	WillRename *FileOperationRegistrationOptions `json:"willRename,omitempty"`
+	DidRename  *FileOperationRegistrationOptions `json:"didRename,omitempty"`
+	DidDelete  *FileOperationRegistrationOptions `json:"didDelete,omitempty"`
+}
+
+type FileOperationRegistrationOptions struct {
+	Filters []FileOperationFilter `json:"filters"`
+}
+
+type FileOperationFilter struct {
+	Pattern FileOperationPattern `json:"pattern"`
+}
+
+type FileOperationPattern struct {
+	Glob    string `json:"glob"`
+	Matches string `json:"matches,omitempty"`
+}
+
+type RenameFilesParams struct {
+	Files []FileRename `json:"files"`
+}
+
+type FileRename struct {
+	OldURI DocumentURI `json:"oldUri"`
+	NewURI DocumentURI `json:"newUri"`
+}
+
+type DeleteFilesParams struct {
+	Files []FileDelete `json:"files"`
+}
+
+type FileDelete struct {
+	URI DocumentURI `json:"uri"`
+}
+
+// FileChangeTypeDeleted is the type of the events of deleted files in
+// workspace/didChangeWatchedFiles, and WatchKindDelete the kind of watchers
+// reporting them.
+const (
+	FileChangeTypeDeleted = 3
+	WatchKindDelete       = 4
+)
+
+type DidChangeWatchedFilesParams struct {
+	Changes []FileEvent `json:"changes"`
+}
+
+type FileEvent struct {
+	URI  DocumentURI `json:"uri"`
+	Type int         `json:"type"`
+}
+
+type RegistrationParams struct {
+	Registrations []Registration `json:"registrations"`
+}
+
+type Registration struct {
+	ID              string      `json:"id"`
+	Method          string      `json:"method"`
+	RegisterOptions interface{} `json:"registerOptions,omitempty"`
+}
+
+type DidChangeWatchedFilesRegistrationOptions struct {
+	Watchers []FileSystemWatcher `json:"watchers"`
+}
+
+type FileSystemWatcher struct {
+	GlobPattern string `json:"globPattern"`
+	Kind        int    `json:"kind,omitempty"`
+}
+
+type WorkspaceFoldersOptions struct {
+	Supported           bool `json:"supported,omitempty"`
+	ChangeNotifications bool `json:"changeNotifications,omitempty"`
+}
+
+type DidChangeWorkspaceFoldersParams struct {
+	Event WorkspaceFoldersChangeEvent `json:"event"`
+}
+
+type WorkspaceFoldersChangeEvent struct {
+	Added   []WorkspaceFolder `json:"added"`
+	Removed []WorkspaceFolder `json:"removed"`
+}
+
+type CodeLensOptions struct {
+	ResolveProvider bool `json:"resolveProvider,omitempty"`
+}
+
+type CodeLensParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+type Command struct {
+	Title     string        `json:"title"`
+	Command   string        `json:"command"`
+	Arguments []interface{} `json:"arguments,omitempty"`
+}
+
+type CodeLens struct {
+	Range   Range    `json:"range"`
+	Command *Command `json:"command,omitempty"`
+}
+
+type ExecuteCommandOptions struct {
+	Commands []string `json:"commands"`
+}
+
+type ExecuteCommandParams struct {
+	Command   string            `json:"command"`
+	Arguments []json.RawMessage `json:"arguments,omitempty"`
+}
+
+type ShowDocumentParams struct {
+	URI       DocumentURI `json:"uri"`
+	TakeFocus bool        `json:"takeFocus,omitempty"`
+}
+
+type TextDocumentPositionParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+type MarkupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+type Hover struct {
+	Contents MarkupContent `json:"contents"`
+	Range    *Range        `json:"range,omitempty"`
+}
+
+type CompletionItem struct {
+	Label         string         `json:"label"`
+	Kind          int            `json:"kind,omitempty"`
+	Tags          []int          `json:"tags,omitempty"`
+	Detail        string         `json:"detail,omitempty"`
+	Documentation *MarkupContent `json:"documentation,omitempty"`
 }
 
 type TextDocumentItem struct {
@@ -62,6 +319,25 @@ type DidOpenTextDocumentParams struct {
 	TextDocument TextDocumentItem `json:"textDocument"`
 }
 
+type DidCloseTextDocumentParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+type VersionedTextDocumentIdentifier struct {
+	URI     DocumentURI `json:"uri"`
+	Version int         `json:"version"`
+}
+
+type TextDocumentContentChangeEvent struct {
+	Range *Range `json:"range,omitempty"`
+	Text  string `json:"text"`
+}
+
+type DidChangeTextDocumentParams struct {
+	TextDocument   VersionedTextDocumentIdentifier  `json:"textDocument"`
+	ContentChanges []TextDocumentContentChangeEvent `json:"contentChanges"`
+}
+
 type DidSaveTextDocumentParams struct {
 	Text         *string                `json:"text"`
 	TextDocument TextDocumentIdentifier `json:"textDocument"`
@@ -104,9 +380,189 @@ type Diagnostic struct {
 	Source             *string                        `json:"source,omitempty"`
 	Message            string                         `json:"message"`
 	RelatedInformation []DiagnosticRelatedInformation `json:"relatedInformation,omitempty"`
+	Data               interface{}                    `json:"data,omitempty"`
 }
 
 type PublishDiagnosticsParams struct {
 	URI         DocumentURI  `json:"uri"`
+	Version     *int         `json:"version,omitempty"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// CodeRequestCancelled is the error code of requests cancelled by the client,
+// and CodeContentModified of requests whose result is stale since the
+// document changed.
+const (
+	CodeRequestCancelled = -32800
+	CodeContentModified  = -32801
+)
+
+type ClientCapabilities struct {
+	TextDocument TextDocumentClientCapabilities `json:"textDocument,omitempty"`
+	Window       WindowClientCapabilities       `json:"window,omitempty"`
+	Workspace    WorkspaceClientCapabilities    `json:"workspace,omitempty"`
+}
+
+type WorkspaceClientCapabilities struct {
+	WorkspaceEdit         WorkspaceEditClientCapabilities `json:"workspaceEdit,omitempty"`
+	CodeLens              RefreshClientCapabilities       `json:"codeLens,omitempty"`
+	Diagnostics           RefreshClientCapabilities       `json:"diagnostics,omitempty"`
+	DidChangeWatchedFiles DynamicRegistrationCapabilities `json:"didChangeWatchedFiles,omitempty"`
+}
+
+type DynamicRegistrationCapabilities struct {
+	DynamicRegistration bool `json:"dynamicRegistration,omitempty"`
+}
+
+type RefreshClientCapabilities struct {
+	RefreshSupport bool `json:"refreshSupport,omitempty"`
+}
+
+type WorkspaceEditClientCapabilities struct {
+	DocumentChanges         bool                     `json:"documentChanges,omitempty"`
+	ChangeAnnotationSupport *ChangeAnnotationSupport `json:"changeAnnotationSupport,omitempty"`
+}
+
+type ChangeAnnotationSupport struct {
+	GroupsOnLabel bool `json:"groupsOnLabel,omitempty"`
+}
+
+type TextDocumentClientCapabilities struct {
+	Synchronization TextDocumentSyncClientCapabilities `json:"synchronization,omitempty"`
+	CodeAction      CodeActionClientCapabilities       `json:"codeAction,omitempty"`
+	Diagnostic      *DynamicRegistrationCapabilities   `json:"diagnostic,omitempty"`
+}
+
+type CodeActionClientCapabilities struct {
+	ResolveSupport *CodeActionResolveSupport `json:"resolveSupport,omitempty"`
+}
+
+type CodeActionResolveSupport struct {
+	Properties []string `json:"properties"`
+}
+
+type TextDocumentSyncClientCapabilities struct {
+	DidSave bool `json:"didSave,omitempty"`
+}
+
+type WindowClientCapabilities struct {
+	WorkDoneProgress bool                         `json:"workDoneProgress,omitempty"`
+	ShowDocument     ShowDocumentClientCapability `json:"showDocument,omitempty"`
+}
+
+type ShowDocumentClientCapability struct {
+	Support bool `json:"support,omitempty"`
+}
+
+type ProgressToken string
+
+type WorkDoneProgressCreateParams struct {
+	Token ProgressToken `json:"token"`
+}
+
+type WorkDoneProgressCancelParams struct {
+	Token ProgressToken `json:"token"`
+}
+
+type ProgressParams struct {
+	Token ProgressToken `json:"token"`
+	Value interface{}   `json:"value"`
+}
+
+type WorkDoneProgressBegin struct {
+	Kind        string `json:"kind"`
+	Title       string `json:"title"`
+	Cancellable bool   `json:"cancellable,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+type WorkDoneProgressReport struct {
+	Kind        string `json:"kind"`
+	Cancellable bool   `json:"cancellable,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+type WorkDoneProgressEnd struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message,omitempty"`
+}
+
+type MessageType int
+
+//nolint:unused,deadcode
+const (
+	MTError MessageType = iota + 1
+	MTWarning
+	MTInfo
+	MTLog
+)
+
+type ShowMessageParams struct {
+	Type    MessageType `json:"type"`
+	Message string      `json:"message"`
+}
+
+type ShowMessageRequestParams struct {
+	Type    MessageType         `json:"type"`
+	Message string              `json:"message"`
+	Actions []MessageActionItem `json:"actions,omitempty"`
+}
+
+type MessageActionItem struct {
+	Title string `json:"title"`
+}
+
+type CodeActionContext struct {
 	Diagnostics []Diagnostic `json:"diagnostics"`
+	Only        []string     `json:"only,omitempty"`
+}
+
+type CodeActionParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+	Context      CodeActionContext      `json:"context"`
+}
+
+type CodeAction struct {
+	Title       string         `json:"title"`
+	Kind        string         `json:"kind,omitempty"`
+	Diagnostics []Diagnostic   `json:"diagnostics,omitempty"`
+	Edit        *WorkspaceEdit `json:"edit,omitempty"`
+	Command     *Command       `json:"command,omitempty"`
+	Data        interface{}    `json:"data,omitempty"`
+}
+
+type TextEdit struct {
+	Range        Range  `json:"range"`
+	NewText      string `json:"newText"`
+	AnnotationID string `json:"annotationId,omitempty"`
+}
+
+type OptionalVersionedTextDocumentIdentifier struct {
+	URI     DocumentURI `json:"uri"`
+	Version *int        `json:"version"`
+}
+
+type TextDocumentEdit struct {
+	TextDocument OptionalVersionedTextDocumentIdentifier `json:"textDocument"`
+	Edits        []TextEdit                              `json:"edits"`
+}
+
+type CreateFile struct {
+	Kind         string      `json:"kind"`
+	URI          DocumentURI `json:"uri"`
+	AnnotationID string      `json:"annotationId,omitempty"`
+}
+
+type ChangeAnnotation struct {
+	Label             string `json:"label"`
+	NeedsConfirmation bool   `json:"needsConfirmation,omitempty"`
+	Description       string `json:"description,omitempty"`
+}
+
+type WorkspaceEdit struct {
+	Changes map[DocumentURI][]TextEdit `json:"changes,omitempty"`
+	// DocumentChanges holds TextDocumentEdit and CreateFile values.
+	DocumentChanges   []interface{}               `json:"documentChanges,omitempty"`
+	ChangeAnnotations map[string]ChangeAnnotation `json:"changeAnnotations,omitempty"`
 }
@@ -1,34 +1,159 @@
 package main
 
 import (
-	"context"
 	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
 	"os"
+	"strings"
 
 	"github.com/sourcegraph/jsonrpc2"
 )
 
 func main() {
-	debug := flag.Bool("debug", false, "show debug log")
+	debug := flag.Bool("debug", false, "show debug log (same as -log-level trace)")
+	addr := flag.String("listen", "", "listen on tcp:HOST:PORT, unix:PATH, npipe:NAME or ws://HOST:PORT/PATH instead of using stdio")
+	stdio := flag.Bool("stdio", false, "communicate over stdin and stdout (default unless -listen is given)")
+	logFile := flag.String("log-file", "", "write logs to the file instead of stderr")
+	logLevelName := flag.String("log-level", "info", "log level: error, info, debug or trace")
+	logFormat := flag.String("log-format", "text", "log format: text or json")
+	metricsAddr := flag.String("metrics-addr", "", "serve metrics over HTTP on the address, e.g. 127.0.0.1:9090")
+	checkUpdates := flag.Bool("check-updates", false, "tell users when a newer release is available")
+	allowedOrigins := flag.String("allowed-origins", "", "comma-separated origins of the browser based editors allowed to connect over ws://, besides the same origin")
+	backend := flag.String("backend", "", "replay the golangci-lint runs recorded in fixture:DIR")
 
+	flag.Usage = usage
 	flag.Parse()
 
-	logger := newStdLogger(*debug)
+	switch flag.Arg(0) {
+	case "doctor":
+		dir := flag.Arg(1)
+		if dir == "" {
+			dir = "."
+		}
 
-	handler := NewHandler(logger)
+		os.Exit(doctor(os.Stdout, dir))
+	case "run":
+		os.Exit(runOnce(os.Stdout, os.Stderr, flag.Args()[1:]))
+	case "update":
+		os.Exit(selfUpdate(os.Stdout, flag.Args()[1:]))
+	}
+
+	level, err := parseLogLevel(*logLevelName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if *debug {
+		level = levelTrace
+	}
+
+	if *logFormat != "text" && *logFormat != "json" {
+		fmt.Fprintf(os.Stderr, "unknown log format %q: want text or json\n", *logFormat)
+		os.Exit(2)
+	}
+
+	var (
+		w     io.Writer = os.Stderr
+		flags int
+	)
+
+	if *logFile != "" {
+		f, err := os.OpenFile(*logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+
+		w, flags = f, log.LstdFlags
+	}
+
+	logger := newStdLogger(w, flags, level, *logFormat == "json")
+
+	if *metricsAddr != "" {
+		go func() {
+			if err := serveMetrics(*metricsAddr); err != nil {
+				logger.Errorf("golangci-lint-langserver: metrics: %s", err)
+			}
+		}()
+	}
 
 	var connOpt []jsonrpc2.ConnOpt
 
-	logger.Printf("golangci-lint-langserver: connections opened")
+	shared := newSharedState()
+	if *checkUpdates {
+		shared.updates = &updateChecker{}
+	}
+
+	if shared.fixtures, err = parseBackendFlag(*backend); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if *stdio || *addr == "" {
+		shared.stdio = true
+		serve(logger, shared, stdrwc{}, connOpt...)
+
+		return
+	}
+
+	ln, err := listen(*addr)
+	if err != nil {
+		logger.Errorf("golangci-lint-langserver: %s", err)
+		os.Exit(1)
+	}
+
+	if u, err := url.Parse(*addr); err == nil && u.Scheme == "ws" {
+		path := u.Path
+		if path == "" {
+			path = "/"
+		}
+
+		err = serveWebSocket(logger, shared, ln, path, splitList(*allowedOrigins), connOpt...)
+		logger.Errorf("golangci-lint-langserver: %s", err)
+		os.Exit(1)
+	}
+
+	if err := serveListener(logger, shared, ln, connOpt...); err != nil {
+		logger.Errorf("golangci-lint-langserver: %s", err)
+		os.Exit(1)
+	}
+}
+
+// splitList returns the non-empty elements of the comma-separated list s.
+func splitList(s string) []string {
+	var list []string
+
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			list = append(list, e)
+		}
+	}
+
+	return list
+}
+
+// hiddenFlags are left out of the usage, being meant for benchmarks and bug
+// reports.
+var hiddenFlags = map[string]bool{"backend": true}
+
+func usage() {
+	fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	fs.SetOutput(flag.CommandLine.Output())
 
-	<-jsonrpc2.NewConn(
-		context.Background(),
-		jsonrpc2.NewBufferedStream(stdrwc{}, jsonrpc2.VSCodeObjectCodec{}),
-		handler,
-		connOpt...,
-	).DisconnectNotify()
+	flag.VisitAll(func(f *flag.Flag) {
+		if !hiddenFlags[f.Name] {
+			fs.Var(f.Value, f.Name, f.Usage)
+			fs.Lookup(f.Name).DefValue = f.DefValue
+		}
+	})
 
-	logger.Printf("golangci-lint-langserver: connections closed")
+	fmt.Fprintf(fs.Output(), "Usage of %s:\n", os.Args[0])
+	fs.PrintDefaults()
 }
 
 type stdrwc struct{}
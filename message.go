@@ -0,0 +1,9 @@
+package main
+
+import "context"
+
+func (h *langHandler) showMessage(typ MessageType, message string) {
+	if err := h.config().conn.Notify(context.Background(), "window/showMessage", &ShowMessageParams{Type: typ, Message: message}); err != nil {
+		h.logger.Errorf("golangci-lint-langserver: %s", err)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"expvar"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// metrics are exported via expvar, and in the Prometheus text format on
+// /metrics when -metrics-addr is given.
+var (
+	metricLintRuns        = expvar.NewInt("golangci_lint_langserver_lint_runs_total")
+	metricLintFailures    = expvar.NewInt("golangci_lint_langserver_lint_failures_total")
+	metricLintCancels     = expvar.NewInt("golangci_lint_langserver_lint_cancellations_total")
+	metricLintSeconds     = expvar.NewFloat("golangci_lint_langserver_lint_duration_seconds_total")
+	metricQueueDepth      = expvar.NewInt("golangci_lint_langserver_queue_depth")
+	metricIssuesPublished = expvar.NewMap("golangci_lint_langserver_issues_published_total")
+)
+
+func recordRun(d time.Duration, err error, cancelled bool) {
+	metricLintRuns.Add(1)
+	metricLintSeconds.Add(d.Seconds())
+
+	switch {
+	case cancelled:
+		metricLintCancels.Add(1)
+	case err != nil:
+		metricLintFailures.Add(1)
+	}
+}
+
+func recordPublished(diagnostics []Diagnostic) {
+	for _, d := range diagnostics {
+		if d.Source != nil && d.Source != &suppressedSource {
+			metricIssuesPublished.Add(*d.Source, 1)
+		}
+	}
+}
+
+// serveMetrics serves the metrics on addr. It blocks until the server fails.
+func serveMetrics(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/metrics", writePrometheus)
+
+	//nolint:gosec
+	return http.ListenAndServe(addr, mux)
+}
+
+func writePrometheus(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	for _, v := range []struct {
+		name string
+		typ  string
+		val  expvar.Var
+	}{
+		{"golangci_lint_langserver_lint_runs_total", "counter", metricLintRuns},
+		{"golangci_lint_langserver_lint_failures_total", "counter", metricLintFailures},
+		{"golangci_lint_langserver_lint_cancellations_total", "counter", metricLintCancels},
+		{"golangci_lint_langserver_lint_duration_seconds_total", "counter", metricLintSeconds},
+		{"golangci_lint_langserver_queue_depth", "gauge", metricQueueDepth},
+	} {
+		fmt.Fprintf(w, "# TYPE %s %s\n%s %s\n", v.name, v.typ, v.name, v.val)
+	}
+
+	const issues = "golangci_lint_langserver_issues_published_total"
+
+	fmt.Fprintf(w, "# TYPE %s counter\n", issues)
+
+	var linters []string
+
+	metricIssuesPublished.Do(func(kv expvar.KeyValue) {
+		linters = append(linters, kv.Key)
+	})
+	sort.Strings(linters)
+
+	for _, linter := range linters {
+		fmt.Fprintf(w, "%s{linter=%q} %s\n", issues, linter, metricIssuesPublished.Get(linter))
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"go/build"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var requirePattern = regexp.MustCompile(`(?m)^\s*require\b`)
+
+// findModule returns the directory of the go.mod file governing dir.
+func findModule(dir string) (string, bool) {
+	for {
+		if fi, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil && !fi.IsDir() {
+			return dir, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+
+		dir = parent
+	}
+}
+
+// inGOPATH reports whether dir is inside the src directory of a GOPATH entry.
+func inGOPATH(dir string) bool {
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		gopath = build.Default.GOPATH
+	}
+
+	target := normalizePath(dir)
+
+	for _, p := range filepath.SplitList(gopath) {
+		if p == "" {
+			continue
+		}
+
+		src := normalizePath(filepath.Join(p, "src"))
+		if target == src || strings.HasPrefix(target, src+string(filepath.Separator)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// forModuleless adapts c to lint the file at path when it is not part of a
+// module: legacy GOPATH projects are linted in GOPATH mode, and stray files
+// alone in their own directory, as scratch directories often hold unrelated
+// files. Modules that require others but lack a go.sum are
+// allowed to update it, as loading their packages fails otherwise.
+func (c *config) forModuleless(path string) *config {
+	if c.executor != nil || c.explicitDir != "" {
+		// the files may be laid out differently where golangci-lint runs
+		return c
+	}
+
+	dir := filepath.Dir(path)
+
+	env := func(fc *config, key, value string) {
+		if _, ok := c.env[key]; ok || os.Getenv(key) != "" {
+			return
+		}
+
+		fc.env = make(map[string]string, len(c.env)+1)
+		for k, v := range c.env {
+			fc.env[k] = v
+		}
+
+		fc.env[key] = value
+	}
+
+	if modDir, ok := findModule(dir); ok {
+		if _, err := os.Stat(filepath.Join(modDir, "go.sum")); err == nil {
+			return c
+		}
+
+		b, err := ioutil.ReadFile(filepath.Join(modDir, "go.mod"))
+		if err != nil || !requirePattern.Match(b) {
+			return c
+		}
+
+		fc := *c
+		env(&fc, "GOFLAGS", "-mod=mod")
+
+		return &fc
+	}
+
+	fc := *c
+	env(&fc, "GO111MODULE", "auto")
+
+	if c.rootDir == "" || !inGOPATH(c.workingDir()) || !inGOPATH(dir) {
+		fc.sessionRoot = c.workspaceRoot()
+		fc.rootDir = dir
+		fc.rootURI = string(pathToURI(dir))
+		fc.dirSource = dirFromFile
+	}
+
+	if !inGOPATH(dir) {
+		fc.singleFile = filepath.Base(path)
+	}
+
+	return &fc
+}
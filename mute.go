@@ -0,0 +1,282 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+const (
+	commandMute   = "golangci-lint.mute"
+	commandUnmute = "golangci-lint.unmute"
+)
+
+// mutedIssue is the fingerprint of an issue muted by the user: it still
+// matches once lines move around it.
+type mutedIssue struct {
+	// File is relative to the workspace root, with slashes.
+	File    string `json:"file"`
+	Linter  string `json:"linter"`
+	Message string `json:"message"`
+}
+
+// muteList holds the issues muted in a workspace, persisted to a file of the
+// user configuration directory so that the shared configuration is left
+// alone.
+type muteList struct {
+	mu     sync.Mutex
+	root   string
+	name   string
+	issues map[mutedIssue]bool
+}
+
+// muteFile returns the file the issues muted in the workspace rooted at
+// rootDir are persisted to.
+func muteFile(rootDir string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(rootDir))
+
+	return filepath.Join(dir, "golangci-lint-langserver", "muted", hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// mutes returns the mute list of the workspace, loading it on first use.
+func (h *langHandler) mutes() *muteList {
+	root := h.config().rootDir
+
+	h.mutesMu.Lock()
+	defer h.mutesMu.Unlock()
+
+	if h.muted != nil && h.muted.root == root {
+		return h.muted
+	}
+
+	l := &muteList{root: root, issues: make(map[mutedIssue]bool)}
+
+	name, err := muteFile(root)
+	if err == nil {
+		l.name = name
+		err = l.load()
+	}
+
+	if err != nil {
+		h.logger.Errorf("golangci-lint-langserver: loading muted issues: %s", err)
+	}
+
+	h.muted = l
+
+	return l
+}
+
+func (l *muteList) load() error {
+	b, err := ioutil.ReadFile(l.name)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var issues []mutedIssue
+	if err := json.Unmarshal(b, &issues); err != nil {
+		return err
+	}
+
+	for _, issue := range issues {
+		l.issues[issue] = true
+	}
+
+	return nil
+}
+
+// save writes the list, l.mu being held.
+func (l *muteList) save() error {
+	if l.name == "" {
+		return nil
+	}
+
+	issues := make([]mutedIssue, 0, len(l.issues))
+	for issue := range l.issues {
+		issues = append(issues, issue)
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+
+		return a.File < b.File || a.File == b.File && (a.Linter < b.Linter || a.Linter == b.Linter && a.Message < b.Message)
+	})
+
+	b, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.name), 0o700); err != nil {
+		return err
+	}
+
+	tmp := l.name + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, l.name)
+}
+
+// fingerprint returns the fingerprint of the issue of data in uri.
+func (l *muteList) fingerprint(uri DocumentURI, data *diagnosticData) mutedIssue {
+	file := uriToPath(string(uri))
+	if rel, err := filepath.Rel(l.root, file); err == nil {
+		file = rel
+	}
+
+	sum := sha256.Sum256([]byte(data.Text))
+
+	return mutedIssue{File: filepath.ToSlash(file), Linter: data.Linter, Message: hex.EncodeToString(sum[:8])}
+}
+
+// filter drops the muted diagnostics of uri.
+func (l *muteList) filter(uri DocumentURI, diagnostics []Diagnostic) []Diagnostic {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.issues) == 0 {
+		return diagnostics
+	}
+
+	kept := make([]Diagnostic, 0, len(diagnostics))
+
+	for _, d := range diagnostics {
+		if data, ok := d.Data.(*diagnosticData); ok && l.issues[l.fingerprint(uri, data)] {
+			continue
+		}
+
+		kept = append(kept, d)
+	}
+
+	return kept
+}
+
+// count returns the number of issues muted in uri.
+func (l *muteList) count(uri DocumentURI) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file := l.fingerprint(uri, &diagnosticData{}).File
+	n := 0
+
+	for issue := range l.issues {
+		if issue.File == file {
+			n++
+		}
+	}
+
+	return n
+}
+
+// set mutes or unmutes the issue of data in uri. Without data, all the
+// issues of uri are unmuted, and without uri all the issues.
+func (l *muteList) set(uri DocumentURI, data *diagnosticData, muted bool) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch {
+	case data != nil && muted:
+		l.issues[l.fingerprint(uri, data)] = true
+	case data != nil:
+		delete(l.issues, l.fingerprint(uri, data))
+	case uri != "":
+		file := l.fingerprint(uri, &diagnosticData{}).File
+
+		for issue := range l.issues {
+			if issue.File == file {
+				delete(l.issues, issue)
+			}
+		}
+	default:
+		l.issues = make(map[mutedIssue]bool)
+	}
+
+	return l.save()
+}
+
+// muteAction returns the code action muting the issue of data for the user
+// only.
+func muteAction(uri DocumentURI, d *Diagnostic, data *diagnosticData) CodeAction {
+	return CodeAction{
+		Title:       "Mute this issue locally",
+		Kind:        codeActionKindQuickFix,
+		Diagnostics: []Diagnostic{*d},
+		Command:     &Command{Title: "Mute this issue locally", Command: commandMute, Arguments: []interface{}{uri, data}},
+	}
+}
+
+// unmuteAction returns the code action unmuting the n issues muted in uri.
+func unmuteAction(uri DocumentURI, n int) CodeAction {
+	title := fmt.Sprintf("Unmute %d locally muted issues in this file", n)
+	if n == 1 {
+		title = "Unmute the locally muted issue in this file"
+	}
+
+	return CodeAction{
+		Title:   title,
+		Kind:    codeActionKindQuickFix,
+		Command: &Command{Title: title, Command: commandUnmute, Arguments: []interface{}{uri}},
+	}
+}
+
+// handleMuteCommand mutes or unmutes issues with the arguments of
+// commandMute or commandUnmute, an optional URI and diagnostic data, and
+// publishes the diagnostics again.
+func (h *langHandler) handleMuteCommand(params *ExecuteCommandParams, muted bool) (result interface{}, err error) {
+	var (
+		uri  DocumentURI
+		data *diagnosticData
+	)
+
+	if len(params.Arguments) > 0 {
+		if err := json.Unmarshal(params.Arguments[0], &uri); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(params.Arguments) > 1 {
+		data = &diagnosticData{}
+		if err := json.Unmarshal(params.Arguments[1], data); err != nil {
+			return nil, err
+		}
+	}
+
+	if muted && data == nil {
+		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidParams, Message: "golangci-lint-langserver: no issue to mute"}
+	}
+
+	if err := h.mutes().set(uri, data, muted); err != nil {
+		return nil, err
+	}
+
+	uris := []DocumentURI{uri}
+	if uri == "" {
+		uris = h.openDocuments()
+	}
+
+	for _, u := range uris {
+		if diagnostics, ok := h.knownDiagnostics(u); ok {
+			h.publish(u, nil, diagnostics)
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+// unusedNolintPattern matches the nolintlint reports of unused directives,
+// optionally naming the linter the directive is unused for.
+var unusedNolintPattern = regexp.MustCompile("directive `(//\\s?nolint[^`]*)` is unused(?: for linter \"?([\\w-]+)\"?)?")
+
+// lineText returns line (0-based) of the file at uri, preferring the content
+// of an open document.
+func (h *langHandler) lineText(uri DocumentURI, line int) (string, bool) {
+	text, ok := h.documentText(uri)
+	if !ok {
+		b, err := ioutil.ReadFile(uriToPath(string(uri)))
+		if err != nil {
+			return "", false
+		}
+
+		text = string(b)
+	}
+
+	lines := strings.Split(text, "\n")
+	if line < 0 || line >= len(lines) {
+		return "", false
+	}
+
+	return strings.TrimSuffix(lines[line], "\r"), true
+}
+
+// removeNolintAction returns the code action removing the unused nolint
+// directive reported by d, or removing only the unused linter from it.
+func (h *langHandler) removeNolintAction(uri DocumentURI, d *Diagnostic, data *diagnosticData) (*CodeAction, bool) {
+	m := unusedNolintPattern.FindStringSubmatch(data.Text)
+	if data.Linter != "nolintlint" || m == nil {
+		return nil, false
+	}
+
+	line := d.Range.Start.Line
+
+	text, ok := h.lineText(uri, line)
+	if !ok {
+		return nil, false
+	}
+
+	directive, unused := m[1], m[2]
+
+	start := strings.Index(text, directive)
+	if start < 0 {
+		return nil, false
+	}
+
+	var edit TextEdit
+
+	if list, linters := nolintLinters(directive); unused != "" && len(linters) > 1 {
+		kept := make([]string, 0, len(linters)-1)
+
+		for _, l := range linters {
+			if l != unused {
+				kept = append(kept, l)
+			}
+		}
+
+		// only the list changes, keeping the explanation
+		edit = TextEdit{
+			Range: Range{
+				Start: Position{Line: line, Character: utf16Offset(text, start+list[0])},
+				End:   Position{Line: line, Character: utf16Offset(text, start+list[1])},
+			},
+			NewText: strings.Join(kept, ","),
+		}
+	} else {
+		// the explanation following the directive goes along with it
+		for start > 0 && (text[start-1] == ' ' || text[start-1] == '\t') {
+			start--
+		}
+
+		edit = TextEdit{Range: Range{
+			Start: Position{Line: line, Character: utf16Offset(text, start)},
+			End:   Position{Line: line, Character: utf16Offset(text, len(text))},
+		}}
+
+		if start == 0 {
+			// the directive was alone on its line
+			edit.Range = Range{Start: Position{Line: line}, End: Position{Line: line + 1}}
+		}
+	}
+
+	return &CodeAction{
+		Title:       "Remove unused nolint directive",
+		Kind:        codeActionKindQuickFix,
+		Diagnostics: []Diagnostic{*d},
+		Edit:        &WorkspaceEdit{Changes: map[DocumentURI][]TextEdit{uri: {edit}}},
+	}, true
+}
+
+// nolintLinters returns the linters listed by a nolint directive, and the
+// byte offsets of the list in the directive.
+func nolintLinters(directive string) (list [2]int, linters []string) {
+	i := strings.Index(directive, ":")
+	if i < 0 {
+		return list, nil
+	}
+
+	end := len(directive)
+	if j := strings.IndexAny(directive[i:], " \t"); j >= 0 {
+		end = i + j
+	}
+
+	list = [2]int{i + 1, end}
+
+	for _, l := range strings.Split(directive[i+1:end], ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			linters = append(linters, l)
+		}
+	}
+
+	return list, linters
+}
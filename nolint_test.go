@@ -0,0 +1,32 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNolintLinters(t *testing.T) {
+	tests := []struct {
+		directive string
+		list      [2]int
+		linters   []string
+	}{
+		{directive: "//nolint"},
+		{directive: "//nolint:errcheck", list: [2]int{9, 17}, linters: []string{"errcheck"}},
+		{directive: "//nolint:errcheck,gosec", list: [2]int{9, 23}, linters: []string{"errcheck", "gosec"}},
+		{directive: "//nolint:errcheck, gosec", list: [2]int{9, 18}, linters: []string{"errcheck"}},
+		{directive: "//nolint:gosec // reason", list: [2]int{9, 14}, linters: []string{"gosec"}},
+		{directive: "//nolint:gosec\tfoo", list: [2]int{9, 14}, linters: []string{"gosec"}},
+		{directive: "//nolint:", list: [2]int{9, 9}},
+		{directive: "//nolint:a,,b", list: [2]int{9, 13}, linters: []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.directive, func(t *testing.T) {
+			list, linters := nolintLinters(tt.directive)
+			if list != tt.list || !reflect.DeepEqual(linters, tt.linters) {
+				t.Errorf("nolintLinters(%q) = %v, %q, want %v, %q", tt.directive, list, linters, tt.list, tt.linters)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+// +build !windows
+
+package main
+
+import (
+	"errors"
+	"net"
+)
+
+var errNoPipes = errors.New("named pipes are only supported on Windows")
+
+// listenPipe fails as named pipes are a Windows feature.
+func listenPipe(string) (net.Listener, error) {
+	return nil, errNoPipes
+}
@@ -0,0 +1,269 @@
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+const (
+	pipeAccessDuplex          = 0x3
+	pipeTypeByte              = 0x0
+	pipeRejectRemoteClients   = 0x8
+	pipeUnlimitedInstances    = 255
+	pipeBufferSize            = 64 << 10
+	fileFlagOverlapped        = 0x40000000
+	fileFlagFirstPipeInstance = 0x80000
+	errorPipeConnected        = syscall.Errno(535)
+)
+
+var (
+	kernel32                = syscall.NewLazyDLL("kernel32.dll")
+	procCreateNamedPipeW    = kernel32.NewProc("CreateNamedPipeW")
+	procConnectNamedPipe    = kernel32.NewProc("ConnectNamedPipe")
+	procDisconnectNamedPipe = kernel32.NewProc("DisconnectNamedPipe")
+	procCreateEventW        = kernel32.NewProc("CreateEventW")
+	procGetOverlappedResult = kernel32.NewProc("GetOverlappedResult")
+
+	errPipeClosed   = errors.New("named pipe closed")
+	errNoDeadlines  = errors.New("named pipes do not support deadlines")
+	errPipeListener = errors.New("named pipe listener closed")
+)
+
+// pipeListener accepts connections on a named pipe, creating a new instance
+// of the pipe for every client.
+type pipeListener struct {
+	name string
+
+	// mu guards closed and pending, the instance waiting for a client.
+	mu      sync.Mutex
+	closed  bool
+	pending syscall.Handle
+}
+
+// listenPipe listens on the named pipe name, such as
+// \\.\pipe\golangci-lint-langserver. It fails if another server owns the pipe.
+func listenPipe(name string) (net.Listener, error) {
+	h, err := createPipe(name, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pipeListener{name: name, pending: h}, nil
+}
+
+func createPipe(name string, first bool) (syscall.Handle, error) {
+	p, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return 0, err
+	}
+
+	flags := uintptr(pipeAccessDuplex | fileFlagOverlapped)
+	if first {
+		flags |= fileFlagFirstPipeInstance
+	}
+
+	r, _, err := procCreateNamedPipeW.Call(
+		uintptr(unsafe.Pointer(p)),
+		flags,
+		pipeTypeByte|pipeRejectRemoteClients,
+		pipeUnlimitedInstances,
+		pipeBufferSize,
+		pipeBufferSize,
+		0,
+		0,
+	)
+	if syscall.Handle(r) == syscall.InvalidHandle {
+		return 0, err
+	}
+
+	return syscall.Handle(r), nil
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	l.mu.Lock()
+
+	if l.closed {
+		l.mu.Unlock()
+
+		return nil, errPipeListener
+	}
+
+	h := l.pending
+	if h == 0 {
+		var err error
+		if h, err = createPipe(l.name, false); err != nil {
+			l.mu.Unlock()
+
+			return nil, err
+		}
+
+		l.pending = h
+	}
+
+	l.mu.Unlock()
+
+	_, err := overlappedIO(h, func(o *syscall.Overlapped) error {
+		return callBool(procConnectNamedPipe, uintptr(h), uintptr(unsafe.Pointer(o)))
+	})
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return nil, errPipeListener
+	}
+
+	l.pending = 0
+
+	// the client may connect between the creation of the instance and
+	// ConnectNamedPipe
+	if err != nil && !errors.Is(err, errorPipeConnected) {
+		syscall.CloseHandle(h) //nolint:errcheck
+
+		return nil, err
+	}
+
+	return &pipeConn{h: h, addr: pipeAddr(l.name)}, nil
+}
+
+func (l *pipeListener) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return nil
+	}
+
+	l.closed = true
+
+	if l.pending == 0 {
+		return nil
+	}
+
+	// unblock Accept
+	syscall.CancelIoEx(l.pending, nil) //nolint:errcheck
+
+	return syscall.CloseHandle(l.pending)
+}
+
+func (l *pipeListener) Addr() net.Addr {
+	return pipeAddr(l.name)
+}
+
+// pipeConn is a client connection to an instance of a named pipe. Reads and
+// writes are overlapped so that they do not wait for each other.
+type pipeConn struct {
+	h    syscall.Handle
+	addr pipeAddr
+
+	closeOnce sync.Once
+}
+
+func (c *pipeConn) Read(b []byte) (int, error) {
+	n, err := overlappedIO(c.h, func(o *syscall.Overlapped) error {
+		return syscall.ReadFile(c.h, b, nil, o)
+	})
+
+	switch {
+	case errors.Is(err, syscall.ERROR_BROKEN_PIPE):
+		return int(n), io.EOF
+	case errors.Is(err, syscall.ERROR_OPERATION_ABORTED):
+		return int(n), errPipeClosed
+	}
+
+	return int(n), err
+}
+
+func (c *pipeConn) Write(b []byte) (int, error) {
+	written := 0
+
+	for written < len(b) {
+		n, err := overlappedIO(c.h, func(o *syscall.Overlapped) error {
+			return syscall.WriteFile(c.h, b[written:], nil, o)
+		})
+
+		written += int(n)
+
+		if err != nil {
+			if errors.Is(err, syscall.ERROR_OPERATION_ABORTED) {
+				err = errPipeClosed
+			}
+
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+func (c *pipeConn) Close() error {
+	err := errPipeClosed
+
+	c.closeOnce.Do(func() {
+		// unblock pending reads and writes
+		syscall.CancelIoEx(c.h, nil)                    //nolint:errcheck
+		callBool(procDisconnectNamedPipe, uintptr(c.h)) //nolint:errcheck
+
+		err = syscall.CloseHandle(c.h)
+	})
+
+	return err
+}
+
+func (c *pipeConn) LocalAddr() net.Addr  { return c.addr }
+func (c *pipeConn) RemoteAddr() net.Addr { return c.addr }
+
+func (c *pipeConn) SetDeadline(time.Time) error      { return errNoDeadlines }
+func (c *pipeConn) SetReadDeadline(time.Time) error  { return errNoDeadlines }
+func (c *pipeConn) SetWriteDeadline(time.Time) error { return errNoDeadlines }
+
+type pipeAddr string
+
+func (a pipeAddr) Network() string { return "npipe" }
+func (a pipeAddr) String() string  { return string(a) }
+
+// overlappedIO starts op on h and waits for it to complete, returning the
+// number of bytes transferred.
+func overlappedIO(h syscall.Handle, op func(*syscall.Overlapped) error) (uint32, error) {
+	r, _, err := procCreateEventW.Call(0, 1, 0, 0)
+	if r == 0 {
+		return 0, err
+	}
+
+	event := syscall.Handle(r)
+	defer syscall.CloseHandle(event) //nolint:errcheck
+
+	// the kernel writes to o until the operation completes, so it must not
+	// live on a goroutine stack
+	o := &syscall.Overlapped{HEvent: event}
+
+	if err := op(o); err != nil && !errors.Is(err, syscall.ERROR_IO_PENDING) {
+		return 0, err
+	}
+
+	var n uint32
+	if err := callBool(procGetOverlappedResult, uintptr(h), uintptr(unsafe.Pointer(o)), uintptr(unsafe.Pointer(&n)), 1); err != nil {
+		return n, err
+	}
+
+	return n, nil
+}
+
+// callBool calls a Windows API returning a BOOL, returning its error if it
+// failed.
+func callBool(proc *syscall.LazyProc, args ...uintptr) error {
+	r, _, err := proc.Call(args...)
+	if r == 0 {
+		return err
+	}
+
+	return nil
+}
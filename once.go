@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// exit codes of the run subcommand
+const (
+	onceExitClean  = 0
+	onceExitIssues = 1
+	onceExitUsage  = 2
+	onceExitFailed = 3
+)
+
+// sarifReport is the SARIF log written by run --once --format sarif.
+type sarifReport struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver struct {
+		Name           string `json:"name"`
+		InformationURI string `json:"informationUri"`
+	} `json:"driver"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId,omitempty"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region struct {
+			StartLine   int `json:"startLine"`
+			StartColumn int `json:"startColumn"`
+			EndLine     int `json:"endLine"`
+			EndColumn   int `json:"endColumn"`
+		} `json:"region"`
+	} `json:"physicalLocation"`
+}
+
+// runOnce implements the run subcommand: it lints the workspace at the
+// directory in args once, as the server would for a client, and writes the
+// diagnostics of every file to w. It returns the exit code of the
+// subcommand.
+func runOnce(w, errw io.Writer, args []string) int {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(errw)
+
+	once := fs.Bool("once", false, "lint the workspace once and exit")
+	format := fs.String("format", "json", "output format: json or sarif")
+
+	if err := fs.Parse(args); err != nil {
+		return onceExitUsage
+	}
+
+	if !*once || *format != "json" && *format != "sarif" || fs.NArg() > 1 {
+		fmt.Fprintln(errw, "usage: golangci-lint-langserver run --once [--format json|sarif] [dir]")
+
+		return onceExitUsage
+	}
+
+	dir := fs.Arg(0)
+	if dir == "" {
+		dir = "."
+	}
+
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+
+	h := newLangHandler(newStdLogger(errw, 0, levelError, false), newSharedState())
+	defer h.close()
+
+	// linting once, as in CI, runs the configured commands of the workspace
+	h.trustWorkspace = true
+
+	cfg := h.newConfig(nil, &InitializeParams{RootURI: string(pathToURI(dir))})
+	if cfg.backendName == defaultBackend && cfg.fallback == "" && cfg.report == nil {
+		if info, err := cfg.golangciLintVersion(); err == nil {
+			cfg.version = info.Version
+		}
+	}
+
+	h.setConfig(cfg)
+
+	pending, err := h.workspaceDiagnostics(cfg, lintArgs())
+	if err != nil {
+		fmt.Fprintf(errw, "golangci-lint-langserver: %s\n", err)
+
+		return onceExitFailed
+	}
+
+	files := make([]PublishDiagnosticsParams, 0, len(pending))
+
+	for _, p := range pending {
+		// the files the server would not publish
+		if h.skipped(p.uri) || len(p.diagnostics) == 0 {
+			continue
+		}
+
+		files = append(files, PublishDiagnosticsParams{URI: p.uri, Diagnostics: p.diagnostics})
+	}
+
+	var out interface{} = files
+	if *format == "sarif" {
+		out = sarifOf(files)
+	}
+
+	var b bytes.Buffer
+
+	enc := json.NewEncoder(&b)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(out); err != nil {
+		fmt.Fprintf(errw, "golangci-lint-langserver: %s\n", err)
+
+		return onceExitFailed
+	}
+
+	if _, err := w.Write(b.Bytes()); err != nil {
+		return onceExitFailed
+	}
+
+	if len(files) > 0 {
+		return onceExitIssues
+	}
+
+	return onceExitClean
+}
+
+// sarifOf converts the diagnostics of files to a SARIF log.
+func sarifOf(files []PublishDiagnosticsParams) *sarifReport {
+	run := sarifRun{Results: []sarifResult{}}
+	run.Tool.Driver.Name = "golangci-lint-langserver"
+	run.Tool.Driver.InformationURI = "https://github.com/nametake/golangci-lint-langserver"
+
+	for _, f := range files {
+		for _, d := range f.Diagnostics {
+			res := sarifResult{
+				Level:   sarifLevel(d.Severity),
+				Message: sarifMessage{Text: d.Message},
+			}
+
+			switch {
+			case d.Source != nil && d.Code != nil:
+				res.RuleID = *d.Source + "/" + *d.Code
+			case d.Source != nil:
+				res.RuleID = *d.Source
+			}
+
+			// sources list every linter of merged duplicates
+			res.RuleID = strings.Split(res.RuleID, ", ")[0]
+
+			var loc sarifLocation
+			loc.PhysicalLocation.ArtifactLocation.URI = string(f.URI)
+			loc.PhysicalLocation.Region.StartLine = d.Range.Start.Line + 1
+			loc.PhysicalLocation.Region.StartColumn = d.Range.Start.Character + 1
+			loc.PhysicalLocation.Region.EndLine = d.Range.End.Line + 1
+			loc.PhysicalLocation.Region.EndColumn = d.Range.End.Character + 1
+			res.Locations = []sarifLocation{loc}
+
+			run.Results = append(run.Results, res)
+		}
+	}
+
+	return &sarifReport{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{run},
+	}
+}
+
+// sarifLevel maps a diagnostic severity to a SARIF result level.
+func sarifLevel(s DiagnosticSeverity) string {
+	switch s {
+	case DSError:
+		return "error"
+	case DSWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
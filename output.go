@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+const (
+	commandShowOutput = "golangci-lint.showOutput"
+
+	// shownOutputSize is how much of the end of stdout is kept for
+	// showOutput, the rest being decoded as it is read.
+	shownOutputSize = 64 << 10
+)
+
+// runOutput is the raw output of a run, kept to debug configuration problems.
+type runOutput struct {
+	command  []string
+	dir      string
+	exitCode int
+	time     time.Time
+	stdout   []byte
+	// dropped is the number of bytes of stdout before those kept.
+	dropped int64
+	stderr  []byte
+}
+
+func (o *runOutput) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "$ %s\n", strings.Join(o.command, " "))
+	fmt.Fprintf(&b, "# in %s at %s, exit code %d\n", o.dir, o.time.Format(time.RFC3339), o.exitCode)
+
+	if o.dropped > 0 {
+		fmt.Fprintf(&b, "\n# stdout, without its first %d bytes\n%s\n", o.dropped, o.stdout)
+	} else {
+		fmt.Fprintf(&b, "\n# stdout\n%s\n", o.stdout)
+	}
+
+	fmt.Fprintf(&b, "\n# stderr\n%s\n", o.stderr)
+
+	return b.String()
+}
+
+// outputBuffer keeps what is written to it, or part of it.
+type outputBuffer interface {
+	io.Writer
+	Bytes() []byte
+}
+
+// tailBuffer is a ring buffer keeping the last bytes written to it.
+type tailBuffer struct {
+	buf []byte
+	// next is where the next byte goes once buf is full, and written the
+	// number of bytes written.
+	next    int
+	written int64
+}
+
+func newTailBuffer(size int) *tailBuffer {
+	return &tailBuffer{buf: make([]byte, 0, size)}
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	t.written += int64(n)
+
+	size := cap(t.buf)
+	if len(p) > size {
+		p = p[len(p)-size:]
+	}
+
+	if room := size - len(t.buf); room > 0 {
+		if room > len(p) {
+			room = len(p)
+		}
+
+		t.buf = append(t.buf, p[:room]...)
+		p = p[room:]
+	}
+
+	for len(p) > 0 {
+		c := copy(t.buf[t.next:], p)
+		p = p[c:]
+		t.next = (t.next + c) % size
+	}
+
+	return n, nil
+}
+
+// Bytes returns the bytes kept, in the order they were written.
+func (t *tailBuffer) Bytes() []byte {
+	if t.next == 0 {
+		return t.buf
+	}
+
+	return append(append([]byte{}, t.buf[t.next:]...), t.buf[:t.next]...)
+}
+
+// droppedBytes returns the number of bytes written to b that it did not keep.
+func droppedBytes(b outputBuffer) int64 {
+	if t, ok := b.(*tailBuffer); ok {
+		return t.written - int64(len(t.buf))
+	}
+
+	return 0
+}
+
+func (h *langHandler) setLastOutput(o *runOutput) {
+	h.outputMu.Lock()
+	defer h.outputMu.Unlock()
+
+	h.lastOutput = o
+}
+
+// handleShowOutput returns the output of the last run. Clients supporting
+// window/showDocument are also asked to open it from a temporary file.
+func (h *langHandler) handleShowOutput(ctx context.Context) (result interface{}, err error) {
+	h.outputMu.Lock()
+	o := h.lastOutput
+	h.outputMu.Unlock()
+
+	if o == nil {
+		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidRequest, Message: "golangci-lint-langserver: nothing has been run yet"}
+	}
+
+	text := o.String()
+
+	cfg := h.config()
+	if !cfg.showDocument {
+		return text, nil
+	}
+
+	path, err := h.writeOutputFile(text)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &ShowDocumentParams{URI: pathToURI(path), TakeFocus: true}
+	if err := cfg.conn.Call(ctx, "window/showDocument", params, nil); err != nil {
+		h.logger.Errorf("golangci-lint-langserver: %s", err)
+	}
+
+	return text, nil
+}
+
+// writeOutputFile writes text to a new temporary file, which other users
+// cannot have created beforehand, and removes the one written previously.
+func (h *langHandler) writeOutputFile(text string) (string, error) {
+	f, err := ioutil.TempFile("", "golangci-lint-langserver-*.log")
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := f.WriteString(text); err != nil {
+		f.Close()
+		_ = os.Remove(f.Name())
+
+		return "", err
+	}
+
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+
+		return "", err
+	}
+
+	h.outputMu.Lock()
+	previous := h.outputFile
+	h.outputFile = f.Name()
+	h.outputMu.Unlock()
+
+	if previous != "" {
+		_ = os.Remove(previous)
+	}
+
+	return f.Name(), nil
+}
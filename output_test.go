@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func TestTailBuffer(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   int
+		writes []string
+		want   string
+	}{
+		{name: "empty", size: 4},
+		{name: "not full", size: 4, writes: []string{"ab"}, want: "ab"},
+		{name: "full", size: 4, writes: []string{"ab", "cd"}, want: "abcd"},
+		{name: "wrapped", size: 4, writes: []string{"abc", "def"}, want: "cdef"},
+		{name: "wrapped twice", size: 4, writes: []string{"abc", "def", "ghij", "k"}, want: "hijk"},
+		{name: "large write", size: 4, writes: []string{"a", "bcdefgh"}, want: "efgh"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTailBuffer(tt.size)
+
+			var written int
+
+			for _, w := range tt.writes {
+				if n, err := b.Write([]byte(w)); n != len(w) || err != nil {
+					t.Fatalf("Write(%q) = %d, %v", w, n, err)
+				}
+
+				written += len(w)
+			}
+
+			if got := string(b.Bytes()); got != tt.want {
+				t.Errorf("Bytes() = %q, want %q", got, tt.want)
+			}
+
+			if got, want := droppedBytes(b), int64(written-len(tt.want)); got != want {
+				t.Errorf("droppedBytes() = %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestTailBufferRandomWrites(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	var all bytes.Buffer
+
+	b := newTailBuffer(100)
+
+	for i := 0; i < 1000; i++ {
+		p := make([]byte, r.Intn(150))
+		r.Read(p)
+
+		all.Write(p)
+		_, _ = b.Write(p)
+
+		want := all.Bytes()
+		if len(want) > 100 {
+			want = want[len(want)-100:]
+		}
+
+		if !bytes.Equal(b.Bytes(), want) {
+			t.Fatalf("after %d writes, Bytes() differs from the last %d bytes written", i+1, len(want))
+		}
+	}
+}
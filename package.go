@@ -0,0 +1,137 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// packageOf returns the import path of the package of filename, looked up
+// with go list once per directory. The directory is used when go list fails.
+func (h *langHandler) packageOf(filename string) string {
+	cfg := h.config().forFile(filename)
+
+	// files linted alone are packages of their own
+	if cfg.singleFile != "" {
+		return filename
+	}
+
+	dir := filepath.Dir(filename)
+
+	h.packages.mu.Lock()
+	pkg, ok := h.packages.pkgs[dir]
+	h.packages.mu.Unlock()
+
+	if ok {
+		return pkg
+	}
+
+	pkg = dir
+
+	if out, err := h.goList(cfg, dir, "-e", "-f", "{{.ImportPath}}", "."); err != nil {
+		h.logger.Debugf("golangci-lint-langserver: go list in %s: %s", dir, err)
+	} else if p := strings.TrimSpace(string(out)); p != "" && p != "." {
+		pkg = p
+	}
+
+	h.packages.mu.Lock()
+	h.packages.pkgs[dir] = pkg
+	h.packages.mu.Unlock()
+
+	return pkg
+}
+
+// goList runs go list with args in dir as the lints of cfg run: with their
+// environment, and in their container or on their remote host.
+func (h *langHandler) goList(cfg *config, dir string, args ...string) ([]byte, error) {
+	c := *cfg
+	c.explicitDir = dir
+
+	if c.executor != nil {
+		c.executor = c.executor.forDir(dir)
+	}
+
+	return h.output(h.ctx, &c, append([]string{"go", "list"}, args...))
+}
+
+// packageFiles returns uris together with the open documents that belong to
+// pkg, without duplicates.
+func (h *langHandler) packageFiles(pkg string, uris []DocumentURI) []DocumentURI {
+	seen := make(map[DocumentURI]bool, len(uris))
+	for _, uri := range uris {
+		seen[uri] = true
+	}
+
+	for _, uri := range h.openDocuments() {
+		if seen[uri] || h.skipped(uri) {
+			continue
+		}
+
+		dir := filepath.Dir(uriToPath(string(uri)))
+
+		h.packages.mu.Lock()
+		p, ok := h.packages.pkgs[dir]
+		h.packages.mu.Unlock()
+
+		if ok && p == pkg {
+			seen[uri] = true
+			uris = append(uris, uri)
+		}
+	}
+
+	return uris
+}
+
+// dependsOn reports whether the package in dir, linted with cfg, imports pkg,
+// directly or not. The dependencies are looked up with go list once per
+// directory.
+func (h *langHandler) dependsOn(cfg *config, dir, pkg string) bool {
+	h.packages.mu.Lock()
+	deps, ok := h.packages.deps[dir]
+	h.packages.mu.Unlock()
+
+	if !ok {
+		deps = make(map[string]bool)
+
+		out, err := h.goList(cfg, dir, "-e", "-deps", "-f", "{{.ImportPath}}", ".")
+		if err != nil {
+			h.logger.Debugf("golangci-lint-langserver: go list -deps in %s: %s", dir, err)
+		}
+
+		for _, p := range strings.Fields(string(out)) {
+			deps[p] = true
+		}
+
+		h.packages.mu.Lock()
+		h.packages.deps[dir] = deps
+		h.packages.mu.Unlock()
+	}
+
+	return deps[pkg]
+}
+
+// enqueueDependents requests a lint of the open documents of the packages
+// importing the package of uri, whose issues may be stale once its API
+// changed.
+func (h *langHandler) enqueueDependents(uri DocumentURI) {
+	filename := uriToPath(string(uri))
+	dir := filepath.Dir(filename)
+	pkg := h.packageOf(filename)
+
+	// the imports of the saved package may have changed
+	h.packages.mu.Lock()
+	delete(h.packages.deps, dir)
+	h.packages.mu.Unlock()
+
+	for _, doc := range h.openDocuments() {
+		docFile := uriToPath(string(doc))
+		docDir := filepath.Dir(docFile)
+
+		if docDir == dir || h.packageOf(docFile) == pkg || !h.dependsOn(h.config().forFile(docFile), docDir, pkg) {
+			continue
+		}
+
+		// the lint must not replay a result predating the change
+		h.cache.forget(docDir)
+		h.enqueue(doc, priorityBackground)
+	}
+}
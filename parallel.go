@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// golangci-lint refuses to run while another instance holds its lock file,
+// such as one started from a terminal. Such runs are retried
+// maxParallelRetries times, waiting parallelRetryDelay, then twice as long
+// every time.
+const (
+	maxParallelRetries = 4
+	parallelRetryDelay = time.Second
+)
+
+// parallelRunning reports whether err is golangci-lint failing because
+// another instance is running.
+func parallelRunning(err error) bool {
+	var toolErr *toolError
+
+	return errors.As(err, &toolErr) &&
+		strings.Contains(strings.ToLower(toolErr.Report+toolErr.Stderr), "parallel golangci-lint is running")
+}
+
+// retryParallel runs run again with a backoff while it fails because
+// another golangci-lint is running. It stops early when h shuts down.
+func (h *langHandler) retryParallel(run func() (*GolangCILintResult, error)) (*GolangCILintResult, error) {
+	result, err := run()
+
+	delay := parallelRetryDelay
+
+	for attempt := 0; attempt < maxParallelRetries && parallelRunning(err); attempt++ {
+		h.logger.Printf("golangci-lint-langserver: another golangci-lint is running, retrying in %s", delay)
+
+		select {
+		case <-h.ctx.Done():
+			return result, err
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		result, err = run()
+	}
+
+	return result, err
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"time"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+const parentPollInterval = 2 * time.Second
+
+// watchParent closes the session once the client process pid exits, so that
+// crashed editors do not leave servers and golangci-lint processes behind.
+func (h *langHandler) watchParent(pid int, conn *jsonrpc2.Conn) {
+	ticker := time.NewTicker(parentPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-ticker.C:
+			if processAlive(pid) {
+				continue
+			}
+
+			h.logger.Printf("golangci-lint-langserver: client process %d exited", pid)
+			h.close()
+
+			if err := conn.Close(); err != nil {
+				h.logger.Debugf("golangci-lint-langserver: %s", err)
+			}
+
+			// a read from stdin blocks until the client writes, which it
+			// never will
+			select {
+			case <-conn.DisconnectNotify():
+			case <-time.After(time.Second):
+				os.Exit(1)
+			}
+
+			return
+		}
+	}
+}
+
+// close kills the running lints and drops the pending ones.
+func (h *langHandler) close() {
+	h.cancel()
+	h.queue.close()
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// caseInsensitiveFS reports whether the default filesystems of the platform
+// compare paths case-insensitively.
+var caseInsensitiveFS = runtime.GOOS == "windows" || runtime.GOOS == "darwin"
+
+// normalizePath returns a form of p that is suitable for comparisons.
+// Symbolic links are resolved so that a file reached through a symlinked
+// workspace root matches the physical path golangci-lint reports.
+func normalizePath(p string) string {
+	p = filepath.Clean(longPath(p))
+
+	if resolved, err := filepath.EvalSymlinks(p); err == nil {
+		p = resolved
+	}
+
+	if caseInsensitiveFS {
+		p = strings.ToLower(p)
+	}
+
+	return p
+}
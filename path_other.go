@@ -0,0 +1,8 @@
+//go:build !windows
+// +build !windows
+
+package main
+
+func longPath(p string) string {
+	return p
+}
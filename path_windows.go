@@ -0,0 +1,29 @@
+//go:build windows
+// +build windows
+
+package main
+
+import "syscall"
+
+// longPath expands 8.3 short names such as C:\PROGRA~1 in p.
+func longPath(p string) string {
+	short, err := syscall.UTF16PtrFromString(p)
+	if err != nil {
+		return p
+	}
+
+	buf := make([]uint16, syscall.MAX_PATH)
+
+	for {
+		n, err := syscall.GetLongPathName(short, &buf[0], uint32(len(buf)))
+		if err != nil || n == 0 {
+			return p
+		}
+
+		if int(n) < len(buf) {
+			return syscall.UTF16ToString(buf[:n])
+		}
+
+		buf = make([]uint16, n)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// utf16Offset converts a byte offset in line to an offset in UTF-16 code
+// units, which is what LSP positions are measured in.
+func utf16Offset(line string, offset int) int {
+	if offset > len(line) {
+		offset = len(line)
+	}
+
+	n := 0
+
+	for _, r := range line[:offset] {
+		n += len(utf16.Encode([]rune{r}))
+	}
+
+	return n
+}
+
+// tokenEnd returns the byte offset where the identifier or literal starting at
+// offset in line ends. If there is no such token, it returns the end of line.
+func tokenEnd(line string, offset int) int {
+	end := offset
+
+	for end < len(line) {
+		r, size := utf8.DecodeRuneInString(line[end:])
+		if !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_') {
+			break
+		}
+
+		end += size
+	}
+
+	if end == offset {
+		return len(line)
+	}
+
+	return end
+}
+
+// issueRange computes the range an issue covers. golangci-lint only reports a
+// start position, so the end is derived from LineRange or from the token found
+// at the start position in SourceLines.
+//
+// File-level issues without a line are mapped to the first line of the file,
+// and issues without a column cover their whole line.
+//
+//nolint:gomnd
+func issueRange(issue *Issue) Range {
+	if issue.Pos.Line <= 0 {
+		return Range{Start: Position{Line: 0, Character: 0}, End: Position{Line: 1, Character: 0}}
+	}
+
+	line := issue.Pos.Line - 1
+	col := issue.Pos.Column - 1
+
+	if col < 0 {
+		return Range{Start: Position{Line: line, Character: 0}, End: Position{Line: line + 1, Character: 0}}
+	}
+
+	if len(issue.SourceLines) == 0 {
+		return Range{
+			Start: Position{Line: line, Character: col},
+			End:   Position{Line: line, Character: col},
+		}
+	}
+
+	// SourceLines starts at LineRange.From when it is reported
+	first := issue.SourceLines[0]
+	if from := issue.LineRange.From; from > 0 && line+1-from >= 0 && line+1-from < len(issue.SourceLines) {
+		first = issue.SourceLines[line+1-from]
+	}
+
+	start := Position{Line: line, Character: utf16Offset(first, col)}
+
+	if to := issue.LineRange.To; to > issue.LineRange.From && to-issue.LineRange.From < len(issue.SourceLines) {
+		last := issue.SourceLines[to-issue.LineRange.From]
+
+		return Range{Start: start, End: Position{Line: to - 1, Character: utf16Offset(last, len(last))}}
+	}
+
+	return Range{Start: start, End: Position{Line: line, Character: utf16Offset(first, tokenEnd(first, col))}}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUTF16Offset(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		offset int
+		want   int
+	}{
+		{name: "ascii", line: "foo := bar", offset: 4, want: 4},
+		{name: "start", line: "foo", offset: 0, want: 0},
+		{name: "two byte runes", line: "é := 1", offset: 3, want: 2},
+		{name: "three byte runes", line: "世界 := 1", offset: 7, want: 3},
+		{name: "surrogate pair", line: "\"😀\" + x", offset: 6, want: 4},
+		{name: "past the end", line: "foo", offset: 10, want: 3},
+		{name: "empty line", line: "", offset: 2, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := utf16Offset(tt.line, tt.offset); got != tt.want {
+				t.Errorf("utf16Offset(%q, %d) = %d, want %d", tt.line, tt.offset, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssueRange(t *testing.T) {
+	issue := func(line, column int, sourceLines []string, from, to int) *Issue {
+		var i Issue
+		i.Pos.Line, i.Pos.Column = line, column
+		i.SourceLines = sourceLines
+		i.LineRange.From, i.LineRange.To = from, to
+
+		return &i
+	}
+
+	r := func(startLine, startChar, endLine, endChar int) Range {
+		return Range{
+			Start: Position{Line: startLine, Character: startChar},
+			End:   Position{Line: endLine, Character: endChar},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		issue *Issue
+		want  Range
+	}{
+		{
+			name:  "file level",
+			issue: issue(0, 0, nil, 0, 0),
+			want:  r(0, 0, 1, 0),
+		},
+		{
+			name:  "no column",
+			issue: issue(3, 0, []string{"\tfoo()"}, 3, 3),
+			want:  r(2, 0, 3, 0),
+		},
+		{
+			name:  "no source lines",
+			issue: issue(3, 5, nil, 0, 0),
+			want:  r(2, 4, 2, 4),
+		},
+		{
+			name:  "token",
+			issue: issue(1, 6, []string{"func main() {"}, 1, 1),
+			want:  r(0, 5, 0, 9),
+		},
+		{
+			name:  "no token",
+			issue: issue(1, 1, []string{"{ x }"}, 1, 1),
+			want:  r(0, 0, 0, 5),
+		},
+		{
+			name:  "multibyte token",
+			issue: issue(1, 5, []string{"var été = 1"}, 1, 1),
+			want:  r(0, 4, 0, 7),
+		},
+		{
+			name:  "multibyte prefix",
+			issue: issue(1, 8, []string{"// é x y"}, 1, 1),
+			want:  r(0, 6, 0, 8),
+		},
+		{
+			name:  "line range",
+			issue: issue(2, 2, []string{"func f() {", "\treturn", "}"}, 1, 3),
+			want:  r(1, 1, 2, 1),
+		},
+		{
+			name:  "line range longer than the source lines",
+			issue: issue(1, 1, []string{"foo"}, 1, 5),
+			want:  r(0, 0, 0, 3),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := issueRange(tt.issue); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("issueRange() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+// +build !windows
+
+package main
+
+import "syscall"
+
+// processAlive reports whether the process pid exists.
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+
+	// EPERM means the process exists but belongs to another user
+	return err == nil || err == syscall.EPERM
+}
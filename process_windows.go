@@ -0,0 +1,28 @@
+//go:build windows
+// +build windows
+
+package main
+
+import "syscall"
+
+// processAlive reports whether the process pid is running.
+func processAlive(pid int) bool {
+	const (
+		processQueryLimitedInformation = 0x1000
+		stillActive                    = 259
+	)
+
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+
+	defer syscall.CloseHandle(h) //nolint:errcheck
+
+	var code uint32
+	if err := syscall.GetExitCodeProcess(h, &code); err != nil {
+		return false
+	}
+
+	return code == stillActive
+}
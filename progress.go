@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+var progressID int64
+
+// progress reports the state of a long running task via workDoneProgress. It
+// does nothing when the client does not support it.
+type progress struct {
+	h     *langHandler
+	token ProgressToken
+	// cancel is called when the user cancels the task, if set.
+	cancel context.CancelFunc
+}
+
+// beginProgress starts reporting a task, which the user may cancel if cancel
+// is not nil.
+func (h *langHandler) beginProgress(title, message string, cancel context.CancelFunc) *progress {
+	cfg := h.config()
+	if !cfg.workDoneProgress {
+		return &progress{}
+	}
+
+	token := ProgressToken(fmt.Sprintf("golangci-lint-langserver/%d", atomic.AddInt64(&progressID, 1)))
+
+	if err := cfg.conn.Call(context.Background(), "window/workDoneProgress/create", &WorkDoneProgressCreateParams{Token: token}, nil); err != nil {
+		h.logger.Errorf("golangci-lint-langserver: %s", err)
+
+		return &progress{}
+	}
+
+	p := &progress{h: h, token: token, cancel: cancel}
+
+	if cancel != nil {
+		h.progressMu.Lock()
+		h.progresses[token] = p
+		h.progressMu.Unlock()
+	}
+
+	p.notify(&WorkDoneProgressBegin{Kind: "begin", Title: title, Cancellable: cancel != nil, Message: message})
+
+	return p
+}
+
+func (p *progress) report(message string) {
+	p.notify(&WorkDoneProgressReport{Kind: "report", Message: message})
+}
+
+func (p *progress) end(message string) {
+	if p.h != nil && p.cancel != nil {
+		p.h.progressMu.Lock()
+		delete(p.h.progresses, p.token)
+		p.h.progressMu.Unlock()
+	}
+
+	p.notify(&WorkDoneProgressEnd{Kind: "end", Message: message})
+}
+
+func (p *progress) notify(value interface{}) {
+	if p.h == nil {
+		return
+	}
+
+	if err := p.h.config().conn.Notify(context.Background(), "$/progress", &ProgressParams{Token: p.token, Value: value}); err != nil {
+		p.h.logger.Errorf("golangci-lint-langserver: %s", err)
+	}
+}
+
+// handleWorkDoneProgressCancel cancels the task of a progress the user
+// dismissed.
+func (h *langHandler) handleWorkDoneProgressCancel(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (result interface{}, err error) {
+	var params WorkDoneProgressCancelParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, err
+	}
+
+	h.progressMu.Lock()
+	p, ok := h.progresses[params.Token]
+	h.progressMu.Unlock()
+
+	if ok {
+		h.logger.Printf("golangci-lint-langserver: cancelling %s", params.Token)
+		p.cancel()
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// projectConfigAnnotation identifies the edits of the configuration file.
+const projectConfigAnnotation = "golangci-lint.config"
+
+var errUnsupportedConfig = errors.New("only YAML golangci-lint configurations can be edited")
+
+// projectConfig is the golangci-lint configuration file of a project as
+// edited by code actions.
+type projectConfig struct {
+	name   string
+	exists bool
+	text   string
+	doc    *yaml.Node
+	// v2 is set for the configuration format of golangci-lint v2.
+	v2 bool
+}
+
+// projectConfigName returns the golangci-lint configuration file that code
+// actions edit in the root directory, without reading it.
+func (h *langHandler) projectConfigName(cfg *config) (string, error) {
+	for _, name := range configNames[2:] {
+		if _, err := os.Stat(filepath.Join(cfg.workingDir(), name)); err == nil {
+			return "", errUnsupportedConfig
+		}
+	}
+
+	for _, name := range configNames[:2] {
+		name = filepath.Join(cfg.workingDir(), name)
+
+		if _, ok := h.documentText(pathToURI(name)); ok || fileExists(name) {
+			return name, nil
+		}
+	}
+
+	return filepath.Join(cfg.workingDir(), configNames[0]), nil
+}
+
+// projectConfig loads the golangci-lint configuration file in the root
+// directory, preferring the content of an open document. A new .golangci.yml
+// is returned when there is none.
+func (h *langHandler) projectConfig(cfg *config) (*projectConfig, error) {
+	name, err := h.projectConfigName(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &projectConfig{name: name}
+
+	text, ok := h.documentText(pathToURI(name))
+	if !ok {
+		if b, err := ioutil.ReadFile(name); err == nil {
+			text, ok = string(b), true
+		}
+	}
+
+	p.exists, p.text = ok, text
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(p.text), &doc); err != nil {
+		return nil, err
+	}
+
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, errUnsupportedConfig
+	}
+
+	p.doc = &doc
+
+	if version := yamlValue(doc.Content[0], "version"); version != nil {
+		p.v2 = version.Value == "2"
+	} else if !p.exists && cfg.version != "" && compareVersions(cfg.version, "2.0.0") >= 0 {
+		p.v2 = true
+
+		version := yamlChild(doc.Content[0], "version", yaml.ScalarNode)
+		version.Value, version.Style = "2", yaml.DoubleQuotedStyle
+	}
+
+	return p, nil
+}
+
+// root returns the top-level mapping of the configuration.
+func (p *projectConfig) root() *yaml.Node {
+	return p.doc.Content[0]
+}
+
+// relPath returns the path of filename relative to the configuration file.
+func (p *projectConfig) relPath(filename string) string {
+	rel, err := filepath.Rel(filepath.Dir(p.name), filename)
+	if err != nil {
+		return filepath.ToSlash(filename)
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// edit returns the workspace edit writing the modified configuration. With
+// annotate, the edit is marked as needing confirmation.
+func (p *projectConfig) edit(annotate bool) (*WorkspaceEdit, error) {
+	var b bytes.Buffer
+
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(p.doc); err != nil {
+		return nil, err
+	}
+
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	uri := pathToURI(p.name)
+	text := TextEdit{NewText: b.String()}
+
+	var create *CreateFile
+	if !p.exists {
+		create = &CreateFile{Kind: "create", URI: uri}
+	} else {
+		last := p.text[strings.LastIndex(p.text, "\n")+1:]
+		text.Range.End = Position{Line: strings.Count(p.text, "\n"), Character: utf16Offset(last, len(last))}
+	}
+
+	edit := &WorkspaceEdit{}
+
+	if annotate {
+		// the whole file is rewritten, so let the user review it first
+		text.AnnotationID = projectConfigAnnotation
+		edit.ChangeAnnotations = map[string]ChangeAnnotation{projectConfigAnnotation: {
+			Label:             "Edit " + filepath.Base(p.name),
+			NeedsConfirmation: true,
+			Description:       "Rewrites the whole golangci-lint configuration file.",
+		}}
+	}
+
+	if create == nil && !annotate {
+		edit.Changes = map[DocumentURI][]TextEdit{uri: {text}}
+
+		return edit, nil
+	}
+
+	if create != nil {
+		create.AnnotationID = text.AnnotationID
+		edit.DocumentChanges = append(edit.DocumentChanges, *create)
+	}
+
+	edit.DocumentChanges = append(edit.DocumentChanges, TextDocumentEdit{
+		TextDocument: OptionalVersionedTextDocumentIdentifier{URI: uri},
+		Edits:        []TextEdit{text},
+	})
+
+	return edit, nil
+}
+
+// yamlValue returns the value of key in mapping m, or nil.
+func yamlValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// yamlChild returns the value of key in mapping m, adding a node of kind if
+// there is none. A null value is replaced.
+func yamlChild(m *yaml.Node, key string, kind yaml.Kind) *yaml.Node {
+	if v := yamlValue(m, key); v != nil {
+		if v.Kind == yaml.ScalarNode && v.Tag == "!!null" && kind != yaml.ScalarNode {
+			*v = yaml.Node{Kind: kind}
+		}
+
+		return v
+	}
+
+	v := &yaml.Node{Kind: kind}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, v)
+
+	return v
+}
+
+// yamlString returns a string scalar node.
+func yamlString(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
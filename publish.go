@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+var suppressedSource = "golangci-lint-langserver"
+
+const (
+	// publishBatchSize notifications are sent at once when publishing the
+	// diagnostics of many files, waiting publishBatchDelay in between so
+	// that slow clients keep up.
+	publishBatchSize  = 20
+	publishBatchDelay = 20 * time.Millisecond
+)
+
+// pendingPublish is the diagnostics of a file waiting to be published.
+type pendingPublish struct {
+	uri         DocumentURI
+	diagnostics []Diagnostic
+}
+
+// publish sends diagnostics for uri to the client, or keeps them for clients
+// pulling them, applying the configured caps on the number of diagnostics. version is the version of the document
+// the diagnostics were computed for, if known. Diagnostics are only replaced
+// once a run completes and are not sent again when unchanged, so that they do
+// not flicker.
+func (h *langHandler) publish(uri DocumentURI, version *int, diagnostics []Diagnostic) {
+	if h.skipped(uri) {
+		return
+	}
+
+	cfg := h.config()
+	diagnostics = h.mutes().filter(uri, diagnostics)
+
+	h.pubMu.Lock()
+	defer h.pubMu.Unlock()
+
+	limit := -1
+	if cfg.maxPerFile > 0 {
+		limit = cfg.maxPerFile
+	}
+
+	if cfg.maxTotal > 0 {
+		remaining := cfg.maxTotal
+
+		for u, published := range h.published {
+			if u != uri {
+				remaining -= len(published)
+			}
+		}
+
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		if limit < 0 || remaining < limit {
+			limit = remaining
+		}
+	}
+
+	counts := countIssues(diagnostics)
+	diagnostics = capDiagnostics(diagnostics, limit)
+
+	if diagnostics == nil {
+		diagnostics = []Diagnostic{}
+	}
+
+	if published, ok := h.published[uri]; cfg.skipUnchanged && ok && reflect.DeepEqual(published, diagnostics) {
+		return
+	}
+
+	if cfg.pullsDiagnostics() {
+		// the client queries the diagnostics once told they changed
+		defer h.refreshOpenDiagnostics(cfg, uri)
+	} else if err := cfg.conn.Notify(
+		context.Background(),
+		"textDocument/publishDiagnostics",
+		&PublishDiagnosticsParams{
+			URI:         uri,
+			Version:     version,
+			Diagnostics: diagnostics,
+		}); err != nil {
+		h.logger.Errorf("golangci-lint-langserver: %s", err)
+
+		return
+	}
+
+	h.published[uri] = diagnostics
+	h.issueCounts[uri] = counts
+
+	recordPublished(diagnostics)
+	h.refreshCodeLenses(cfg, uri)
+}
+
+// publishAll publishes the diagnostics of many files in batches, starting
+// with the open documents.
+func (h *langHandler) publishAll(pending []pendingPublish) {
+	open := make(map[DocumentURI]bool)
+	for _, uri := range h.openDocuments() {
+		open[uri] = true
+	}
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		return open[pending[i].uri] && !open[pending[j].uri]
+	})
+
+	for i, p := range pending {
+		if i > 0 && i%publishBatchSize == 0 {
+			select {
+			case <-h.ctx.Done():
+				return
+			case <-time.After(publishBatchDelay):
+			}
+		}
+
+		h.publish(p.uri, nil, p.diagnostics)
+	}
+}
+
+// forgetPublished forgets the diagnostics published for uri, so that the next
+// ones are sent even if unchanged.
+func (h *langHandler) forgetPublished(uri DocumentURI) {
+	h.pubMu.Lock()
+	defer h.pubMu.Unlock()
+
+	delete(h.published, uri)
+	delete(h.issueCounts, uri)
+}
+
+// capDiagnostics truncates diagnostics to limit entries, replacing the rest
+// with an informational diagnostic at the top of the file. A negative limit
+// means no limit.
+func capDiagnostics(diagnostics []Diagnostic, limit int) []Diagnostic {
+	if limit < 0 || len(diagnostics) <= limit {
+		return diagnostics
+	}
+
+	suppressed := len(diagnostics) - limit
+
+	capped := make([]Diagnostic, 0, limit+1)
+	capped = append(capped, Diagnostic{
+		Severity: DSInformation,
+		Source:   &suppressedSource,
+		Message:  fmt.Sprintf("%d more issues suppressed", suppressed),
+	})
+
+	return append(capped, diagnostics[:limit]...)
+}
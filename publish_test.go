@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCapDiagnostics(t *testing.T) {
+	diagnostics := func(n int) []Diagnostic {
+		ds := make([]Diagnostic, 0, n)
+		for i := 0; i < n; i++ {
+			ds = append(ds, Diagnostic{Message: fmt.Sprint(i)})
+		}
+
+		return ds
+	}
+
+	tests := []struct {
+		name     string
+		n, limit int
+		// want lists the messages of the capped diagnostics.
+		want []string
+	}{
+		{name: "no limit", n: 3, limit: -1, want: []string{"0", "1", "2"}},
+		{name: "under the limit", n: 2, limit: 3, want: []string{"0", "1"}},
+		{name: "at the limit", n: 3, limit: 3, want: []string{"0", "1", "2"}},
+		{name: "over the limit", n: 5, limit: 2, want: []string{"3 more issues suppressed", "0", "1"}},
+		{name: "zero limit", n: 2, limit: 0, want: []string{"2 more issues suppressed"}},
+		{name: "empty", n: 0, limit: 0, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := capDiagnostics(diagnostics(tt.n), tt.limit)
+			if len(got) != len(tt.want) {
+				t.Fatalf("capDiagnostics() returned %d diagnostics, want %d", len(got), len(tt.want))
+			}
+
+			for i, d := range got {
+				if d.Message != tt.want[i] {
+					t.Errorf("diagnostic %d = %q, want %q", i, d.Message, tt.want[i])
+				}
+			}
+
+			if len(got) > tt.n && (got[0].Severity != DSInformation || got[0].Source != &suppressedSource) {
+				t.Errorf("summary = %+v, want an information from %s", got[0], suppressedSource)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// pullsDiagnostics reports whether the client of c pulls the diagnostics with
+// textDocument/diagnostic instead of being sent them, which needs it to be
+// told with workspace/diagnostic/refresh when they changed.
+func (c *config) pullsDiagnostics() bool {
+	return c.diagnosticPull && c.diagnosticRefresh
+}
+
+// handleTextDocumentDiagnostic returns the diagnostics of the last run of a
+// file, which pulling clients get instead of publishDiagnostics.
+func (h *langHandler) handleTextDocumentDiagnostic(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (result interface{}, err error) {
+	var params DocumentDiagnosticParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, err
+	}
+
+	h.pubMu.Lock()
+	diagnostics, ok := h.published[params.TextDocument.URI]
+	h.pubMu.Unlock()
+
+	if !ok {
+		diagnostics = []Diagnostic{}
+	}
+
+	return &FullDocumentDiagnosticReport{Kind: "full", Items: diagnostics}, nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+func TestPullDiagnostics(t *testing.T) {
+	s := newFixtureSession(t, fixtureOutputOf(fixtureIssue("errcheck", "Error return value is not checked", "main.go", 4, 2)), nil)
+	defer s.close()
+
+	s.h.updateConfig(func(c *config) {
+		c.diagnosticPull = true
+		c.diagnosticRefresh = true
+	})
+
+	uri := s.uri("main.go")
+	s.h.openDocument(TextDocumentItem{URI: uri, LanguageID: "go", Version: 1})
+	s.h.lintAndPublish(s.root, []DocumentURI{uri}, modeFull)
+
+	if published := s.flush(t); len(published) != 0 {
+		t.Errorf("published %v to a client pulling diagnostics", published)
+	}
+
+	// the refresh is requested in the background
+	deadline := time.Now().Add(time.Second)
+
+	for {
+		s.mu.Lock()
+		refreshes := s.refreshes
+		s.mu.Unlock()
+
+		if refreshes > 0 {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("diagnostics not refreshed")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	params, err := json.Marshal(DocumentDiagnosticParams{TextDocument: TextDocumentIdentifier{URI: uri}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw := json.RawMessage(params)
+
+	result, err := s.h.handleTextDocumentDiagnostic(context.Background(), nil, &jsonrpc2.Request{Method: "textDocument/diagnostic", Params: &raw})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := result.(*FullDocumentDiagnosticReport)
+	if report.Kind != "full" || len(report.Items) != 1 || report.Items[0].Message != "Error return value is not checked" {
+		t.Errorf("textDocument/diagnostic = %+v, want the errcheck issue", report)
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// priority orders lint requests. Higher priorities are served first.
+type priority int
+
+const (
+	// priorityBackground is used for lints nobody is waiting for, such as
+	// refreshing stale results or open files after a reload.
+	priorityBackground priority = iota
+	// priorityInteractive is used for files the user is working on.
+	priorityInteractive
+)
+
+type queueItem struct {
+	uris     []DocumentURI
+	priority priority
+	mode     lintMode
+	seq      uint64
+	// queued is when the package was first requested.
+	queued time.Time
+}
+
+// lintQueue holds the pending lint requests. Requests for files of the same
+// package are coalesced so that a single run serves all of them. The package
+// with the highest priority is served first, the most recently requested one
+// among equals. A full run requested for a package supersedes a fast one.
+type lintQueue struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending map[string]*queueItem
+	seq     uint64
+	closed  bool
+}
+
+func newLintQueue() *lintQueue {
+	q := &lintQueue{pending: make(map[string]*queueItem)}
+	q.cond = sync.NewCond(&q.mu)
+
+	return q
+}
+
+// push queues a lint of uri, which belongs to pkg. It reports false if the
+// queue is closed.
+func (q *lintQueue) push(pkg string, uri DocumentURI, prio priority, mode lintMode) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return false
+	}
+
+	q.seq++
+
+	item, ok := q.pending[pkg]
+	if !ok {
+		item = &queueItem{priority: prio, mode: mode, queued: time.Now()}
+		q.pending[pkg] = item
+	}
+
+	if mode == modeFull {
+		item.mode = modeFull
+	}
+
+	if prio >= item.priority {
+		item.priority = prio
+		item.seq = q.seq
+	}
+
+	if !containsURI(item.uris, uri) {
+		item.uris = append(item.uris, uri)
+	}
+
+	metricQueueDepth.Set(int64(len(q.pending)))
+	q.cond.Signal()
+
+	return true
+}
+
+// pop waits for the next package to lint and returns it with the files
+// requested for it. It reports false once the queue is closed.
+func (q *lintQueue) pop() (string, []DocumentURI, lintMode, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.pending) == 0 && !q.closed {
+		q.cond.Wait()
+	}
+
+	if q.closed {
+		return "", nil, modeFull, false
+	}
+
+	var (
+		next string
+		best *queueItem
+	)
+
+	for pkg, item := range q.pending {
+		if best == nil || item.priority > best.priority || item.priority == best.priority && item.seq > best.seq {
+			next, best = pkg, item
+		}
+	}
+
+	delete(q.pending, next)
+	metricQueueDepth.Set(int64(len(q.pending)))
+
+	return next, best.uris, best.mode, true
+}
+
+// snapshot returns copies of the pending requests by package, in the order
+// pop serves them.
+func (q *lintQueue) snapshot() ([]string, []queueItem) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pkgs := make([]string, 0, len(q.pending))
+	for pkg := range q.pending {
+		pkgs = append(pkgs, pkg)
+	}
+
+	sort.Slice(pkgs, func(i, j int) bool {
+		a, b := q.pending[pkgs[i]], q.pending[pkgs[j]]
+
+		return a.priority > b.priority || a.priority == b.priority && a.seq > b.seq
+	})
+
+	items := make([]queueItem, len(pkgs))
+	for i, pkg := range pkgs {
+		items[i] = *q.pending[pkg]
+		items[i].uris = append([]DocumentURI{}, items[i].uris...)
+	}
+
+	return pkgs, items
+}
+
+// close drops the pending requests and wakes up pop.
+func (q *lintQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.closed = true
+	q.pending = nil
+	metricQueueDepth.Set(0)
+	q.cond.Broadcast()
+}
+
+func containsURI(uris []DocumentURI, uri DocumentURI) bool {
+	for _, u := range uris {
+		if u == uri {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"time"
+)
+
+const configPollInterval = 2 * time.Second
+
+// watchConfig reloads the configuration when SIGHUP is received or when one
+// of the configuration files changes, until shutdown.
+func (h *langHandler) watchConfig() {
+	sig := make(chan os.Signal, 1)
+	notifyReload(sig)
+
+	defer signal.Stop(sig)
+
+	ticker := time.NewTicker(configPollInterval)
+	defer ticker.Stop()
+
+	root := h.config().rootDir
+	files := configFiles(root)
+	mtimes := modTimes(files)
+
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-sig:
+			h.reload("signal received")
+		case <-ticker.C:
+			// the root may be known only once folders are added
+			if cfg := h.config(); cfg.rootDir != root {
+				root = cfg.rootDir
+				files = configFiles(root)
+				mtimes = modTimes(files)
+			}
+
+			if current := modTimes(files); !sameModTimes(mtimes, current) {
+				mtimes = current
+				h.reload("configuration file changed")
+			}
+		}
+	}
+}
+
+func modTimes(files []string) []time.Time {
+	mtimes := make([]time.Time, len(files))
+
+	for i, name := range files {
+		if fi, err := os.Stat(name); err == nil {
+			mtimes[i] = fi.ModTime()
+		}
+	}
+
+	return mtimes
+}
+
+func sameModTimes(a, b []time.Time) bool {
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// refreshOpenDiagnostics asks the client to query the diagnostics again once
+// those of uri changed, if uri is open and the client pulls diagnostics.
+func (h *langHandler) refreshOpenDiagnostics(cfg *config, uri DocumentURI) {
+	if _, ok := h.documentText(uri); ok {
+		h.refreshDiagnostics(cfg)
+	}
+}
+
+// refreshDiagnostics asks the client, if it pulls diagnostics, to query them
+// again as the configuration or the runs they depend on changed.
+func (h *langHandler) refreshDiagnostics(cfg *config) {
+	if !cfg.diagnosticRefresh {
+		return
+	}
+
+	go func() {
+		if err := cfg.conn.Call(context.Background(), "workspace/diagnostic/refresh", nil, nil); err != nil {
+			h.logger.Debugf("golangci-lint-langserver: refreshing diagnostics: %s", err)
+		}
+	}()
+}
+
+// reload rebuilds the config, re-detects golangci-lint and lints the open
+// documents again.
+func (h *langHandler) reload(reason string) {
+	h.logger.Printf("golangci-lint-langserver: reloading configuration: %s", reason)
+
+	h.paramsMu.Lock()
+	old := h.config()
+	cfg := h.newConfig(old.conn, old.params)
+	cfg.linterToggles = old.linterToggles
+	cfg.discoverRoots = old.discoverRoots
+	h.setConfig(cfg)
+	h.paramsMu.Unlock()
+	// the cache is shared with the sessions of other workspaces
+	h.cache.clearUnder(append(old.sessionDirs(), cfg.sessionDirs()...))
+	h.clearGitIgnored()
+
+	// an edited workspace configuration needs trusting again
+	go h.confirmTrust(cfg)
+
+	h.runMu.Lock()
+	if cfg.install != nil {
+		if err := h.ensureInstalled(*cfg.install); err != nil {
+			h.logger.Errorf("golangci-lint-langserver: installing golangci-lint: %s", err)
+		}
+	}
+	h.checkVersion()
+	h.runMu.Unlock()
+	h.refreshDiagnostics(cfg)
+
+	if old.rootDir == "" && cfg.rootDir != "" {
+		go h.startWorkspace(cfg)
+	}
+
+	if cfg.triggers.manualOnly() {
+		return
+	}
+
+	for _, uri := range h.openDocuments() {
+		h.enqueue(uri, priorityBackground)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build windows
+// +build windows
+
+package main
+
+import "os"
+
+// notifyReload does nothing as there is no SIGHUP on Windows; configuration
+// file changes still trigger reloads.
+func notifyReload(chan<- os.Signal) {}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// fileOperations are the renames and deletions the client notifies: Go files
+// and the folders that may contain some.
+var fileOperations = &FileOperationRegistrationOptions{Filters: []FileOperationFilter{
+	{Pattern: FileOperationPattern{Glob: "**/*.go", Matches: "file"}},
+	{Pattern: FileOperationPattern{Glob: "**", Matches: "folder"}},
+}}
+
+// registerWatchers asks the client to report deleted Go files, for the
+// deletions made outside of the editor.
+func (h *langHandler) registerWatchers(cfg *config) {
+	params := &RegistrationParams{Registrations: []Registration{{
+		ID:     "golangci-lint-langserver.watchers",
+		Method: "workspace/didChangeWatchedFiles",
+		RegisterOptions: &DidChangeWatchedFilesRegistrationOptions{Watchers: []FileSystemWatcher{
+			{GlobPattern: "**/*.go", Kind: WatchKindDelete},
+		}},
+	}}}
+
+	if err := cfg.conn.Call(context.Background(), "client/registerCapability", params, nil); err != nil {
+		h.logger.Debugf("golangci-lint-langserver: registering file watchers: %s", err)
+	}
+}
+
+func (h *langHandler) handleWorkspaceWillRenameFiles(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (result interface{}, err error) {
+	var params RenameFilesParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, err
+	}
+
+	// the lints waiting for the old files would publish for URIs that are
+	// about to disappear
+	for _, f := range params.Files {
+		h.debouncer.cancel(f.OldURI)
+	}
+
+	return nil, nil
+}
+
+func (h *langHandler) handleWorkspaceDidRenameFiles(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (result interface{}, err error) {
+	var params RenameFilesParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, err
+	}
+
+	h.trees.forget()
+
+	for _, f := range params.Files {
+		h.moveFile(f.OldURI, f.NewURI)
+	}
+
+	return nil, nil
+}
+
+func (h *langHandler) handleWorkspaceDidDeleteFiles(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (result interface{}, err error) {
+	var params DeleteFilesParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, err
+	}
+
+	h.trees.forget()
+
+	for _, f := range params.Files {
+		h.moveFile(f.URI, "")
+	}
+
+	return nil, nil
+}
+
+func (h *langHandler) handleWorkspaceDidChangeWatchedFiles(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (result interface{}, err error) {
+	var params DidChangeWatchedFilesParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, err
+	}
+
+	h.trees.forget()
+
+	for _, change := range params.Changes {
+		// the file may be back by the time the event arrives, as when
+		// switching branches
+		if change.Type == FileChangeTypeDeleted && !fileExists(uriToPath(string(change.URI))) {
+			h.moveFile(change.URI, "")
+		}
+	}
+
+	return nil, nil
+}
+
+// moveFile moves the diagnostics of the file or folder old to newURI, or
+// clears them if newURI is "", and lints the packages of both again.
+func (h *langHandler) moveFile(old, newURI DocumentURI) {
+	oldPath := uriToPath(string(old))
+	newPath := ""
+
+	if newURI != "" {
+		newPath = uriToPath(string(newURI))
+	}
+
+	moved := h.takePublished(old)
+
+	for uri, diagnostics := range moved {
+		if newURI == "" {
+			continue
+		}
+
+		target := newURI + uri[len(old):]
+		if h.isGoDocument(target) {
+			h.publish(target, nil, diagnostics)
+		}
+	}
+
+	h.moveKnown(oldPath, newPath)
+
+	h.logger.Event(levelDebug, "golangci-lint-langserver: file moved", logFields{
+		"from":        old,
+		"to":          newURI,
+		"diagnostics": len(moved),
+	})
+
+	if h.config().triggers.manualOnly() {
+		return
+	}
+
+	dirs := []string{normalizePath(filepath.Dir(oldPath))}
+	if newPath != "" {
+		dirs = append(dirs, normalizePath(filepath.Dir(newPath)))
+	}
+
+	for _, uri := range h.openDocuments() {
+		p := normalizePath(uriToPath(string(uri)))
+
+		// the open files of a renamed folder are linted too
+		affected := newPath != "" && underPath(p, normalizePath(newPath))
+
+		for _, dir := range dirs {
+			affected = affected || filepath.Dir(p) == dir
+		}
+
+		if affected {
+			h.enqueue(uri, priorityBackground)
+		}
+	}
+
+	if newURI != "" && h.isGoDocument(newURI) {
+		h.enqueue(newURI, priorityBackground)
+	}
+}
+
+// takePublished clears the diagnostics published for uri and the files under
+// it, returning them.
+func (h *langHandler) takePublished(uri DocumentURI) map[DocumentURI][]Diagnostic {
+	cfg := h.config()
+	taken := make(map[DocumentURI][]Diagnostic)
+
+	h.pubMu.Lock()
+	defer h.pubMu.Unlock()
+
+	for u, diagnostics := range h.published {
+		if u != uri && !strings.HasPrefix(string(u), string(uri)+"/") {
+			continue
+		}
+
+		if cfg.pullsDiagnostics() {
+			defer h.refreshOpenDiagnostics(cfg, u)
+		} else if err := cfg.conn.Notify(
+			context.Background(),
+			"textDocument/publishDiagnostics",
+			&PublishDiagnosticsParams{URI: u, Diagnostics: []Diagnostic{}}); err != nil {
+			h.logger.Errorf("golangci-lint-langserver: %s", err)
+
+			continue
+		}
+
+		taken[u] = diagnostics
+
+		delete(h.published, u)
+		delete(h.issueCounts, u)
+		h.refreshCodeLenses(cfg, u)
+	}
+
+	return taken
+}
+
+// moveKnown moves the diagnostics earlier runs found for the file or folder
+// old to newPath, or forgets them if newPath is "".
+func (h *langHandler) moveKnown(old, newPath string) {
+	old = normalizeRemoved(old)
+	if newPath != "" {
+		newPath = normalizePath(newPath)
+	}
+
+	h.knownMu.Lock()
+	defer h.knownMu.Unlock()
+
+	for _, files := range h.known {
+		moved := make(map[string][]Diagnostic)
+
+		for f, diagnostics := range files {
+			if underPath(f, old) {
+				moved[f] = diagnostics
+				delete(files, f)
+			}
+		}
+
+		for f, diagnostics := range moved {
+			if newPath != "" {
+				files[newPath+f[len(old):]] = diagnostics
+			}
+		}
+	}
+
+	for f, used := range h.lastUsed {
+		if !underPath(f, old) {
+			continue
+		}
+
+		delete(h.lastUsed, f)
+
+		if newPath != "" {
+			h.lastUsed[newPath+f[len(old):]] = used
+		}
+	}
+}
+
+// normalizeRemoved normalizes the path of a file that may no longer exist,
+// whose symbolic links normalizePath cannot resolve but in its directory.
+func normalizeRemoved(p string) string {
+	base := filepath.Base(p)
+	if caseInsensitiveFS {
+		base = strings.ToLower(base)
+	}
+
+	return filepath.Join(normalizePath(filepath.Dir(p)), base)
+}
+
+// underPath reports whether p is dir or a path in it.
+func underPath(p, dir string) bool {
+	return p == dir || strings.HasPrefix(p, dir+string(filepath.Separator))
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// offlineReport is a golangci-lint JSON or SARIF report produced elsewhere,
+// typically by CI, whose issues are published instead of running the linter.
+type offlineReport struct {
+	path string
+	// root is the directory the relative paths of the report are resolved
+	// against.
+	root string
+}
+
+// newOfflineReport returns the report at path, relative to root.
+func newOfflineReport(root, path string) *offlineReport {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+
+	return &offlineReport{path: filepath.Clean(path), root: root}
+}
+
+// load reads the report again, so that a new download is picked up by the
+// next lint.
+func (r *offlineReport) load() (*GolangCILintResult, error) {
+	b, err := ioutil.ReadFile(r.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var sarif struct {
+		Runs json.RawMessage `json:"runs"`
+	}
+
+	var result GolangCILintResult
+
+	if json.Unmarshal(b, &sarif) == nil && sarif.Runs != nil {
+		err = sarifBackend{}.decode(bytes.NewReader(b), &result)
+	} else {
+		err = decodeResult(bytes.NewReader(b), &result)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result.Issues {
+		pos := &result.Issues[i].Pos
+		if !filepath.IsAbs(pos.Filename) {
+			pos.Filename = filepath.Join(r.root, filepath.FromSlash(pos.Filename))
+		}
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// resourceFlags returns the golangci-lint flags limiting the resources a run
+// takes from the editor and gopls, whether it waits for other instances and
+// whether it reports its timings.
+func (c *config) resourceFlags() []string {
+	var flags []string
+
+	if c.concurrency > 0 {
+		flags = append(flags, "--concurrency", strconv.Itoa(c.concurrency))
+	}
+
+	if c.allowParallelRunners {
+		flags = append(flags, "--allow-parallel-runners")
+	}
+
+	return append(flags, c.statsFlags()...)
+}
+
+// lintEnv returns the environment variables set for golangci-lint runs.
+func (c *config) lintEnv() []string {
+	var env []string
+
+	if c.gogc != "" {
+		env = append(env, "GOGC="+c.gogc)
+	}
+
+	if c.cacheDir != "" {
+		env = append(env, "GOLANGCI_LINT_CACHE="+c.cacheDir)
+	}
+
+	keys := make([]string, 0, len(c.env))
+	for k := range c.env {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		env = append(env, k+"="+c.env[k])
+	}
+
+	if driver := c.packagesDriver; driver != "" {
+		// a relative driver is usually a script of the workspace
+		if c.executor == nil && strings.ContainsRune(driver, filepath.Separator) && !filepath.IsAbs(driver) {
+			driver = filepath.Join(c.workingDir(), driver)
+		}
+
+		env = append(env, "GOPACKAGESDRIVER="+driver)
+	}
+
+	if c.executor != nil {
+		// the driver settings of the server environment do not reach the executor
+		for _, kv := range os.Environ() {
+			if strings.HasPrefix(kv, "GOPACKAGESDRIVER") && !hasEnv(env, kv[:strings.Index(kv, "=")]) {
+				env = append(env, kv)
+			}
+		}
+	}
+
+	return env
+}
+
+// hasEnv reports whether env sets the variable key.
+func hasEnv(env []string, key string) bool {
+	for _, kv := range env {
+		if strings.HasPrefix(kv, key+"=") {
+			return true
+		}
+	}
+
+	return false
+}
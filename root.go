@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// startWorkspace runs the lints made once the workspace is known: on start,
+// or when clients initialize without a root, once it is added.
+func (h *langHandler) startWorkspace(cfg *config) {
+	if cfg.rootDir == "" {
+		if cfg.lintWorkspaceOnStart || cfg.warmCache {
+			h.logger.Printf("golangci-lint-langserver: no workspace root yet, deferring the start lints")
+		}
+
+		return
+	}
+
+	switch {
+	case cfg.lintWorkspaceOnStart:
+		// the run warms the cache as well
+		h.lintWorkspace()
+	case cfg.warmCache:
+		h.warmUp()
+	}
+}
+
+func (h *langHandler) handleWorkspaceDidChangeWorkspaceFolders(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (result interface{}, err error) {
+	var params DidChangeWorkspaceFoldersParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, err
+	}
+
+	removed := make(map[string]bool)
+	for _, folder := range params.Event.Removed {
+		removed[filepath.Clean(uriToPath(folder.URI))] = true
+	}
+
+	h.paramsMu.Lock()
+	h.updateConfig(func(c *config) {
+		p := *c.params
+		p.WorkspaceFolders = nil
+
+		for _, folder := range c.params.WorkspaceFolders {
+			if !removed[filepath.Clean(uriToPath(folder.URI))] {
+				p.WorkspaceFolders = append(p.WorkspaceFolders, folder)
+			}
+		}
+
+		p.WorkspaceFolders = append(p.WorkspaceFolders, params.Event.Added...)
+		c.params = &p
+		// the client manages the folders from now on
+		c.discoverRoots = false
+	})
+	h.paramsMu.Unlock()
+
+	go h.reload("workspace folders changed")
+
+	return nil, nil
+}
+
+// adoptModule makes the module of the file at path a workspace folder when the
+// client opened no workspace, reporting whether linting waits for the config
+// to reload.
+func (h *langHandler) adoptModule(path string) bool {
+	cfg := h.config()
+	if !cfg.discoverRoots {
+		return false
+	}
+
+	dir, ok := findModule(filepath.Dir(path))
+	if !ok {
+		return false
+	}
+
+	dir = filepath.Clean(dir)
+	if _, ok := cfg.folders[dir]; ok {
+		return false
+	}
+
+	added := false
+
+	h.paramsMu.Lock()
+	h.updateConfig(func(c *config) {
+		for _, folder := range c.params.WorkspaceFolders {
+			if filepath.Clean(uriToPath(folder.URI)) == dir {
+				// a reload is pending
+				return
+			}
+		}
+
+		p := *c.params
+		p.WorkspaceFolders = append(append([]WorkspaceFolder{}, c.params.WorkspaceFolders...), WorkspaceFolder{
+			URI:  string(pathToURI(dir)),
+			Name: filepath.Base(dir),
+		})
+		c.params = &p
+		added = true
+	})
+	h.paramsMu.Unlock()
+
+	if added {
+		// the reload lints the open documents again
+		go h.reload("module found at " + dir)
+	}
+
+	return true
+}
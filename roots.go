@@ -0,0 +1,101 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// workspaceRoots returns the normalized directories whose files get
+// diagnostics: the workspace root and folders, and with includeReplaced the
+// local targets of the replace directives of their go.mod files.
+func workspaceRoots(rootDir string, folders map[string]*FolderOptions, includeReplaced bool) []string {
+	dirs := []string{rootDir}
+	for dir := range folders {
+		dirs = append(dirs, dir)
+	}
+
+	if includeReplaced {
+		for _, dir := range dirs {
+			dirs = append(dirs, replaceDirs(dir)...)
+		}
+	}
+
+	roots := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		roots = append(roots, normalizePath(dir))
+	}
+
+	return roots
+}
+
+// replaceDirs returns the local directories modules are replaced with in the
+// go.mod file of dir.
+func replaceDirs(dir string) []string {
+	b, err := ioutil.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil
+	}
+
+	var (
+		dirs  []string
+		block bool
+	)
+
+	for _, line := range strings.Split(string(b), "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+
+		line = strings.TrimSpace(line)
+
+		switch {
+		case block && line == ")":
+			block = false
+
+			continue
+		case line == "replace (":
+			block = true
+
+			continue
+		case !block && !strings.HasPrefix(line, "replace "):
+			continue
+		}
+
+		i := strings.Index(line, "=>")
+		if i < 0 {
+			continue
+		}
+
+		fields := strings.Fields(line[i+2:])
+		if len(fields) != 1 {
+			// a module path with a version
+			continue
+		}
+
+		target := filepath.FromSlash(fields[0])
+		if !filepath.IsAbs(target) && !strings.HasPrefix(fields[0], "./") && !strings.HasPrefix(fields[0], "../") {
+			continue
+		}
+
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(dir, target)
+		}
+
+		dirs = append(dirs, filepath.Clean(target))
+	}
+
+	return dirs
+}
+
+// inWorkspace reports whether the normalized path is below one of the
+// workspace roots, or the root of the config itself.
+func (c *config) inWorkspace(path string) bool {
+	for _, root := range append([]string{normalizePath(c.rootDir)}, c.roots...) {
+		if path == root || strings.HasPrefix(path, root+string(filepath.Separator)) {
+			return true
+		}
+	}
+
+	return false
+}
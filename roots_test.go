@@ -0,0 +1,73 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReplaceDirs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golangci-lint-langserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name string
+		mod  string
+		want []string
+	}{
+		{name: "no go.mod"},
+		{
+			name: "no replacement",
+			mod:  "module example.com/m\n\nrequire example.com/dep v1.0.0\n",
+		},
+		{
+			name: "relative",
+			mod:  "module example.com/m\n\nreplace example.com/dep => ../dep\n",
+			want: []string{filepath.Join(filepath.Dir(dir), "dep")},
+		},
+		{
+			name: "block",
+			mod:  "module example.com/m\n\nreplace (\n\texample.com/a => ./a // local\n\texample.com/b v1.0.0 => ./b\n)\n",
+			want: []string{filepath.Join(dir, "a"), filepath.Join(dir, "b")},
+		},
+		{
+			name: "absolute",
+			mod:  "module example.com/m\n\nreplace example.com/dep => " + filepath.ToSlash(filepath.Join(dir, "abs")) + "\n",
+			want: []string{filepath.Join(dir, "abs")},
+		},
+		{
+			name: "module replacement",
+			mod:  "module example.com/m\n\nreplace example.com/dep => example.com/fork v1.2.0\n",
+		},
+		{
+			name: "module path without version",
+			mod:  "module example.com/m\n\nreplace example.com/dep => example.com/fork\n",
+		},
+		{
+			name: "commented out",
+			mod:  "module example.com/m\n\n// replace example.com/dep => ../dep\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := filepath.Join(dir, "go.mod")
+			os.Remove(name)
+
+			if tt.mod != "" {
+				if err := ioutil.WriteFile(name, []byte(tt.mod), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := replaceDirs(dir); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("replaceDirs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
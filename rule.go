@@ -0,0 +1,67 @@
+package main
+
+import "regexp"
+
+const ruleActionIgnore = "ignore"
+
+// Rule changes the severity of, or ignores, the issues whose text matches
+// Pattern, optionally only for the issues of Linter.
+type Rule struct {
+	Pattern  string `json:"pattern"`
+	Linter   string `json:"linter,omitempty"`
+	Severity string `json:"severity,omitempty"`
+	Action   string `json:"action,omitempty"`
+}
+
+type rule struct {
+	pattern  *regexp.Regexp
+	linter   string
+	severity DiagnosticSeverity
+	ignore   bool
+}
+
+func (h *langHandler) compileRules(rules []Rule) []rule {
+	compiled := make([]rule, 0, len(rules))
+
+	for _, r := range rules {
+		pattern, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			h.logger.Errorf("golangci-lint-langserver: rule %q: %s", r.Pattern, err)
+
+			continue
+		}
+
+		severity, _ := parseSeverity(r.Severity)
+
+		compiled = append(compiled, rule{
+			pattern:  pattern,
+			linter:   r.Linter,
+			severity: severity,
+			ignore:   r.Action == ruleActionIgnore,
+		})
+	}
+
+	return compiled
+}
+
+// applyRules returns the severity of issue after applying the first matching
+// rule, and whether the issue should be published at all.
+func (c *config) applyRules(issue *Issue, severity DiagnosticSeverity) (DiagnosticSeverity, bool) {
+	for _, r := range c.rules {
+		if r.linter != "" && r.linter != issue.FromLinter || !r.pattern.MatchString(issue.Text) {
+			continue
+		}
+
+		if r.ignore {
+			return severity, false
+		}
+
+		if r.severity != 0 {
+			severity = r.severity
+		}
+
+		break
+	}
+
+	return severity, true
+}
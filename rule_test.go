@@ -0,0 +1,45 @@
+package main
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestApplyRules(t *testing.T) {
+	h := &langHandler{logger: newStdLogger(ioutil.Discard, 0, levelError, false)}
+
+	c := &config{rules: h.compileRules([]Rule{
+		{Pattern: "^G104", Linter: "gosec", Action: ruleActionIgnore},
+		{Pattern: "should have comment", Severity: "hint"},
+		{Pattern: "is not checked", Linter: "errcheck", Severity: "error"},
+		{Pattern: "is not checked", Severity: "information"},
+		{Pattern: "("},
+	})}
+
+	tests := []struct {
+		name     string
+		linter   string
+		text     string
+		severity DiagnosticSeverity
+		want     DiagnosticSeverity
+		wantOK   bool
+	}{
+		{name: "no rule", linter: "unused", text: "func f is unused", severity: DSWarning, want: DSWarning, wantOK: true},
+		{name: "ignored", linter: "gosec", text: "G104: Errors unhandled.", severity: DSWarning, want: DSWarning},
+		{name: "other linter", linter: "revive", text: "G104: Errors unhandled.", severity: DSWarning, want: DSWarning, wantOK: true},
+		{name: "any linter", linter: "revive", text: "exported function F should have comment", severity: DSWarning, want: DSHint, wantOK: true},
+		{name: "first matching rule", linter: "errcheck", text: "Error return value is not checked", severity: DSWarning, want: DSError, wantOK: true},
+		{name: "later rule", linter: "gosec", text: "value is not checked", severity: DSWarning, want: DSInformation, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := &Issue{FromLinter: tt.linter, Text: tt.text}
+
+			got, ok := c.applyRules(issue, tt.severity)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("applyRules() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
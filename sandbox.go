@@ -0,0 +1,233 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/build"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	sandboxBwrap = "bwrap"
+	sandboxExec  = "sandbox-exec"
+)
+
+var (
+	errUnknownSandbox = errors.New("unknown sandbox tool")
+	errNoSandboxTool  = errors.New("readOnlyWorkspace needs a sandbox tool")
+
+	// sandboxEnv are the variables a sandboxed golangci-lint gets besides
+	// those of allowEnv, sandboxGoEnv the settings of the Go toolchain and
+	// golangci-lint, and sandboxEnvPrefixes the prefixes of the others, but
+	// not the credentials of the user, even those named GO*.
+	sandboxEnv   = []string{"PATH", "HOME", "USER", "LOGNAME", "TMPDIR", "TMP", "TEMP", "LANG", "TERM", "SYSTEMROOT"}
+	sandboxGoEnv = []string{
+		"GO111MODULE", "GOARCH", "GOBIN", "GOCACHE", "GODEBUG", "GOENV", "GOEXPERIMENT", "GOFIPS140",
+		"GOFLAGS", "GOINSECURE", "GOMODCACHE", "GONOPROXY", "GONOSUMDB", "GOOS", "GOPATH", "GOPRIVATE",
+		"GOPROXY", "GOROOT", "GOSUMDB", "GOTMPDIR", "GOTOOLCHAIN", "GOVCS", "GOWORK", "GOPACKAGESDRIVER",
+		"GO386", "GOAMD64", "GOARM", "GOARM64", "GOMIPS", "GOMIPS64", "GOPPC64", "GORISCV64", "GOWASM",
+		"GOGC", "GOMAXPROCS", "GOMEMLIMIT", "GOTRACEBACK", "GCCGO",
+		"GOLANGCI_LINT_CACHE", "GL_DEBUG",
+	}
+	sandboxEnvPrefixes = []string{"CGO_", "LC_"}
+)
+
+// SandboxOptions restrict what golangci-lint, and the code it runs while
+// loading packages, can reach, for untrusted repositories. Without a tool,
+// only the environment is filtered.
+type SandboxOptions struct {
+	// Tool is bwrap on Linux or sandbox-exec on macOS.
+	Tool              string   `json:"tool,omitempty"`
+	ReadOnlyWorkspace bool     `json:"readOnlyWorkspace,omitempty"`
+	AllowNetwork      bool     `json:"allowNetwork,omitempty"`
+	AllowEnv          []string `json:"allowEnv,omitempty"`
+}
+
+// filterEnv returns the variables of environ that the sandbox lets through.
+func (s *SandboxOptions) filterEnv(environ []string) []string {
+	filtered := make([]string, 0, len(environ))
+
+	for _, kv := range environ {
+		name := kv
+		if i := strings.Index(kv, "="); i >= 0 {
+			name = kv[:i]
+		}
+
+		if s.allowsEnv(name) {
+			filtered = append(filtered, kv)
+		}
+	}
+
+	return filtered
+}
+
+func (s *SandboxOptions) allowsEnv(name string) bool {
+	for _, allowed := range [][]string{sandboxEnv, sandboxGoEnv, s.AllowEnv} {
+		for _, n := range allowed {
+			if strings.EqualFold(n, name) {
+				return true
+			}
+		}
+	}
+
+	for _, prefix := range sandboxEnvPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writableDirs returns the directories outside the workspace that the Go
+// toolchain and golangci-lint write to: their caches, and the module cache
+// when modules may be downloaded.
+func (s *SandboxOptions) writableDirs(cacheDir string) []string {
+	var dirs []string
+
+	if dir, err := os.UserCacheDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+
+	for _, name := range []string{"GOCACHE", "GOLANGCI_LINT_CACHE"} {
+		if dir := os.Getenv(name); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	if cacheDir != "" {
+		dirs = append(dirs, cacheDir)
+	}
+
+	if s.AllowNetwork {
+		if dir := os.Getenv("GOMODCACHE"); dir != "" {
+			dirs = append(dirs, dir)
+		} else if build.Default.GOPATH != "" {
+			dirs = append(dirs, filepath.Join(filepath.SplitList(build.Default.GOPATH)[0], "pkg", "mod"))
+		}
+	}
+
+	return dirs
+}
+
+// wrap returns the command line running command in the sandbox, with the
+// workspace directories dirs.
+func (s *SandboxOptions) wrap(command, dirs []string, cacheDir string) ([]string, error) {
+	switch s.Tool {
+	case "":
+		if s.ReadOnlyWorkspace {
+			return nil, errNoSandboxTool
+		}
+
+		return command, nil
+	case sandboxBwrap:
+		return append(s.bwrapArgs(dirs, cacheDir), command...), nil
+	case sandboxExec:
+		return append([]string{sandboxExec, "-p", s.profile(dirs, cacheDir)}, command...), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", errUnknownSandbox, s.Tool)
+}
+
+// bwrapArgs returns the bubblewrap arguments exposing the file system
+// read-only but for the workspace, the caches and a private temporary
+// directory.
+func (s *SandboxOptions) bwrapArgs(dirs []string, cacheDir string) []string {
+	args := []string{sandboxBwrap, "--die-with-parent", "--unshare-all"}
+	if s.AllowNetwork {
+		args = append(args, "--share-net")
+	}
+
+	args = append(args, "--ro-bind", "/", "/", "--dev", "/dev", "--proc", "/proc", "--tmpfs", os.TempDir())
+
+	for _, dir := range s.writableDirs(cacheDir) {
+		args = append(args, "--bind-try", dir, dir)
+	}
+
+	// the workspace is bound last as it may be in the temporary directory
+	bind := "--bind"
+	if s.ReadOnlyWorkspace {
+		bind = "--ro-bind"
+	}
+
+	for _, dir := range dirs {
+		args = append(args, bind, dir, dir)
+	}
+
+	return append(args, "--")
+}
+
+// profile returns the sandbox-exec profile denying writes but to the
+// workspace, the caches and the temporary directory.
+func (s *SandboxOptions) profile(dirs []string, cacheDir string) string {
+	writable := append([]string{os.TempDir(), "/dev"}, s.writableDirs(cacheDir)...)
+	if !s.ReadOnlyWorkspace {
+		writable = append(writable, dirs...)
+	}
+
+	var b strings.Builder
+
+	b.WriteString("(version 1)\n(allow default)\n")
+
+	if !s.AllowNetwork {
+		b.WriteString("(deny network*)\n")
+	}
+
+	b.WriteString("(deny file-write*)\n(allow file-write*")
+
+	for _, dir := range writable {
+		// the profile matches the paths with symbolic links resolved
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			dir = resolved
+		}
+
+		fmt.Fprintf(&b, " (subpath %s)", schemeQuote(dir))
+	}
+
+	b.WriteString(")\n")
+
+	return b.String()
+}
+
+// schemeQuote quotes s as a string of a sandbox-exec profile.
+func schemeQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// sandboxed returns command wrapped in the sandbox of c, if any. Runs on
+// another machine or in a container are not sandboxed.
+func (c *config) sandboxed(command []string) ([]string, error) {
+	if c.sandbox == nil || c.executor != nil {
+		return command, nil
+	}
+
+	dirs := []string{c.workingDir()}
+	if c.rootDir != "" && !underPath(c.workingDir(), c.rootDir) {
+		dirs = append(dirs, c.rootDir)
+	}
+
+	return c.sandbox.wrap(command, dirs, c.cacheDir)
+}
+
+// commandEnv returns the environment golangci-lint runs with, or nil for that
+// of the server.
+func (c *config) commandEnv() []string {
+	if c.executor != nil {
+		// the environment of the server does not reach the executor
+		return nil
+	}
+
+	env := c.lintEnv()
+
+	if c.sandbox != nil {
+		return append(c.sandbox.filterEnv(os.Environ()), env...)
+	}
+
+	if len(env) == 0 {
+		return nil
+	}
+
+	return append(os.Environ(), env...)
+}
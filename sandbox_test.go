@@ -0,0 +1,55 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSandboxFilterEnv(t *testing.T) {
+	environ := []string{
+		"PATH=/usr/bin",
+		"HOME=/home/u",
+		"GOFLAGS=-mod=mod",
+		"GOPROXY=https://proxy.golang.org",
+		"GOLANGCI_LINT_CACHE=/tmp/cache",
+		"CGO_ENABLED=0",
+		"LC_ALL=C",
+		"GOOGLE_APPLICATION_CREDENTIALS=/home/u/key.json",
+		"GOOGLE_API_KEY=secret",
+		"GOPHER_TOKEN=secret",
+		"AWS_SECRET_ACCESS_KEY=secret",
+		"SSH_AUTH_SOCK=/tmp/agent",
+		"INVALID",
+	}
+
+	tests := []struct {
+		name     string
+		allowEnv []string
+		want     []string
+	}{
+		{
+			name: "default",
+			want: []string{
+				"PATH=/usr/bin", "HOME=/home/u", "GOFLAGS=-mod=mod", "GOPROXY=https://proxy.golang.org",
+				"GOLANGCI_LINT_CACHE=/tmp/cache", "CGO_ENABLED=0", "LC_ALL=C",
+			},
+		},
+		{
+			name:     "allowed",
+			allowEnv: []string{"SSH_AUTH_SOCK"},
+			want: []string{
+				"PATH=/usr/bin", "HOME=/home/u", "GOFLAGS=-mod=mod", "GOPROXY=https://proxy.golang.org",
+				"GOLANGCI_LINT_CACHE=/tmp/cache", "CGO_ENABLED=0", "LC_ALL=C", "SSH_AUTH_SOCK=/tmp/agent",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SandboxOptions{AllowEnv: tt.allowEnv}
+			if got := s.filterEnv(environ); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
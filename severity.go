@@ -0,0 +1,89 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+func parseSeverity(s string) (DiagnosticSeverity, bool) {
+	switch strings.ToLower(s) {
+	case "error":
+		return DSError, true
+	case "warning":
+		return DSWarning, true
+	case "information", "info":
+		return DSInformation, true
+	case "hint":
+		return DSHint, true
+	}
+
+	return 0, false
+}
+
+// severityName is the inverse of parseSeverity, returning "" for the default
+// severity.
+func severityName(s DiagnosticSeverity) string {
+	switch s {
+	case DSError:
+		return "error"
+	case DSWarning:
+		return "warning"
+	case DSInformation:
+		return "information"
+	case DSHint:
+		return "hint"
+	}
+
+	return ""
+}
+
+// severity returns the severity of diagnostics reported by linter.
+func (c *config) severity(linter string) DiagnosticSeverity {
+	if s, ok := c.severities[linter]; ok {
+		return s
+	}
+
+	if s, ok := c.severities["default"]; ok {
+		return s
+	}
+
+	return DSWarning
+}
+
+// testFlags returns the flags of golangci-lint run telling whether to lint
+// test files, unless golangci-lint decides.
+func (c *config) testFlags() []string {
+	if c.lintTests == nil {
+		return nil
+	}
+
+	return []string{"--tests=" + strconv.FormatBool(*c.lintTests)}
+}
+
+// testedSeverity returns severity downgraded to testSeverity for the issues
+// in the test file at path, so that tests can follow relaxed rules.
+func (c *config) testedSeverity(path string, severity DiagnosticSeverity) DiagnosticSeverity {
+	if c.testSeverity != 0 && severity < c.testSeverity && strings.HasSuffix(path, "_test.go") {
+		return c.testSeverity
+	}
+
+	return severity
+}
+
+// severe reports whether diagnostics of severity s are published, given
+// minSeverity. Lower values are more severe.
+func (c *config) severe(s DiagnosticSeverity) bool {
+	return c.minSeverity == 0 || s <= c.minSeverity
+}
+
+func parseSeverities(m map[string]string) map[string]DiagnosticSeverity {
+	severities := make(map[string]DiagnosticSeverity, len(m))
+
+	for linter, s := range m {
+		if severity, ok := parseSeverity(s); ok {
+			severities[linter] = severity
+		}
+	}
+
+	return severities
+}
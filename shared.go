@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// sharedState is shared by the sessions of a server, so that several editor
+// windows on the same repository reuse the results of each other's runs
+// instead of running golangci-lint again.
+type sharedState struct {
+	cache    *resultCache
+	trees    *treeCache
+	packages *packageCache
+	runs     *runGroup
+	// updates is set to tell users about newer releases.
+	updates *updateChecker
+	// fixtures, when set, replace the golangci-lint runs of every session.
+	fixtures *fixtureSet
+	// stdio is set when the server has a single session, over stdio.
+	stdio bool
+}
+
+func newSharedState() *sharedState {
+	return &sharedState{
+		cache:    newResultCache(),
+		trees:    newTreeCache(),
+		packages: newPackageCache(),
+		runs:     &runGroup{calls: make(map[string]*runCall)},
+	}
+}
+
+// packageCache remembers per directory the import path of its package and
+// those of the package dependencies.
+type packageCache struct {
+	mu   sync.Mutex
+	pkgs map[string]string
+	deps map[string]map[string]bool
+}
+
+func newPackageCache() *packageCache {
+	return &packageCache{pkgs: make(map[string]string), deps: make(map[string]map[string]bool)}
+}
+
+// runGroup coalesces identical runs requested concurrently by several
+// sessions into one.
+type runGroup struct {
+	mu    sync.Mutex
+	calls map[string]*runCall
+}
+
+type runCall struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	// waiters counts the callers waiting for the run.
+	waiters int
+	result  *GolangCILintResult
+	err     error
+}
+
+// runKey identifies the runs of cfg in dir whose packages hash to hash: runs
+// with another command, environment or executor are not the same.
+func runKey(cfg *config, dir, hash string) string {
+	return strings.Join([]string{
+		dir,
+		hash,
+		strings.Join(lintArgs()(cfg), " "),
+		strings.Join(cfg.commandEnv(), " "),
+	}, "\x00")
+}
+
+// do runs fn unless a run with the same key is in progress, in which case its
+// result is waited for and returned instead. The run does not depend on the
+// context of the caller starting it: it is cancelled once every caller
+// stopped waiting for it on the cancellation of its ctx.
+func (g *runGroup) do(ctx context.Context, key string, fn func(context.Context) (*GolangCILintResult, error)) (*GolangCILintResult, error) {
+	g.mu.Lock()
+
+	c, ok := g.calls[key]
+	if !ok {
+		runCtx, cancel := context.WithCancel(context.Background())
+		c = &runCall{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = c
+
+		go func() {
+			c.result, c.err = fn(runCtx)
+			cancel()
+			close(c.done)
+
+			g.mu.Lock()
+			if g.calls[key] == c {
+				delete(g.calls, key)
+			}
+			g.mu.Unlock()
+		}()
+	}
+
+	c.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.result, c.err
+	case <-ctx.Done():
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	c.waiters--
+	if c.waiters == 0 {
+		c.cancel()
+
+		// later callers start another run instead of waiting for this one
+		if g.calls[key] == c {
+			delete(g.calls, key)
+		}
+	}
+
+	return nil, ctx.Err()
+}
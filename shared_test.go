@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunGroupCancel(t *testing.T) {
+	g := &runGroup{calls: make(map[string]*runCall)}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	killed := make(chan struct{})
+
+	fn := func(ctx context.Context) (*GolangCILintResult, error) {
+		close(started)
+
+		select {
+		case <-release:
+			return &GolangCILintResult{}, nil
+		case <-ctx.Done():
+			close(killed)
+
+			return nil, ctx.Err()
+		}
+	}
+
+	type outcome struct {
+		result *GolangCILintResult
+		err    error
+	}
+
+	first, cancelFirst := context.WithCancel(context.Background())
+	defer cancelFirst()
+
+	firstDone := make(chan outcome, 1)
+
+	go func() {
+		result, err := g.do(first, "key", fn)
+		firstDone <- outcome{result, err}
+	}()
+
+	<-started
+
+	secondDone := make(chan outcome, 1)
+
+	go func() {
+		result, err := g.do(context.Background(), "key", fn)
+		secondDone <- outcome{result, err}
+	}()
+
+	// wait until the second caller joined the run
+	for {
+		g.mu.Lock()
+		waiters := g.calls["key"].waiters
+		g.mu.Unlock()
+
+		if waiters == 2 {
+			break
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	cancelFirst()
+
+	if o := <-firstDone; !errors.Is(o.err, context.Canceled) {
+		t.Fatalf("cancelled caller got %v, want %v", o.err, context.Canceled)
+	}
+
+	select {
+	case <-killed:
+		t.Fatal("run cancelled while a caller still waits for it")
+	default:
+	}
+
+	close(release)
+
+	if o := <-secondDone; o.err != nil || o.result == nil {
+		t.Fatalf("waiting caller got %v, %v", o.result, o.err)
+	}
+}
+
+func TestRunGroupCancelLast(t *testing.T) {
+	g := &runGroup{calls: make(map[string]*runCall)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	killed := make(chan struct{})
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := g.do(ctx, "key", func(ctx context.Context) (*GolangCILintResult, error) {
+			<-ctx.Done()
+			close(killed)
+
+			return nil, ctx.Err()
+		})
+		done <- err
+	}()
+
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("do() = %v, want %v", err, context.Canceled)
+	}
+
+	select {
+	case <-killed:
+	case <-time.After(time.Second):
+		t.Fatal("run not cancelled once its last caller left")
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// skipped reports whether uri should neither be linted nor published: it is
+// not a Go file, is listed in ignoreFiles, is below a vendor or testdata
+// directory, or is ignored by git, unless included by configuration.
+func (h *langHandler) skipped(uri DocumentURI) bool {
+	cfg := h.config()
+	if !h.isGoDocument(uri) || cfg.ignored(uri) {
+		return true
+	}
+
+	path := uriToPath(string(uri))
+
+	if rel, err := filepath.Rel(cfg.rootDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+		for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(rel)), "/") {
+			if dir == "vendor" && !cfg.includeVendor || dir == "testdata" && !cfg.includeTestdata {
+				return true
+			}
+		}
+	}
+
+	return !cfg.includeGitIgnored && h.gitIgnored(path)
+}
+
+// isGoDocument reports whether uri is a Go file, as told by the language of
+// the open document or else by its extension. Clients may open other
+// documents with the server, such as .golangci.yml.
+func (h *langHandler) isGoDocument(uri DocumentURI) bool {
+	h.docsMu.Lock()
+	doc, ok := h.docs[uri]
+	h.docsMu.Unlock()
+
+	if ok && doc.languageID != "" {
+		return doc.languageID == "go"
+	}
+
+	return strings.HasSuffix(string(uri), ".go")
+}
+
+// isBuildFile reports whether uri is a file the diagnostics of Go files
+// depend on: go.mod, go.sum, go.work or the golangci-lint configuration.
+func isBuildFile(uri DocumentURI) bool {
+	base := filepath.Base(uriToPath(string(uri)))
+
+	for _, name := range append([]string{"go.mod", "go.sum", "go.work"}, configNames...) {
+		if base == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// gitIgnored reports whether git ignores path. Results are cached until the
+// configuration is reloaded.
+func (h *langHandler) gitIgnored(path string) bool {
+	h.ignoredMu.Lock()
+	defer h.ignoredMu.Unlock()
+
+	if ignored, ok := h.gitIgnoredFiles[path]; ok {
+		return ignored
+	}
+
+	// check-ignore fails when the path is not ignored or not in a repository
+	_, err := git(filepath.Dir(path), "check-ignore", "-q", path)
+	h.gitIgnoredFiles[path] = err == nil
+
+	return err == nil
+}
+
+func (h *langHandler) clearGitIgnored() {
+	h.ignoredMu.Lock()
+	defer h.ignoredMu.Unlock()
+
+	h.gitIgnoredFiles = make(map[string]bool)
+}
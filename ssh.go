@@ -0,0 +1,83 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+type SSHOptions struct {
+	Host     string        `json:"host"`
+	User     string        `json:"user,omitempty"`
+	Port     int           `json:"port,omitempty"`
+	Args     []string      `json:"args,omitempty"`
+	Mappings []PathMapping `json:"mappings,omitempty"`
+	Workdir  string        `json:"workdir,omitempty"`
+}
+
+type sshExecutor struct {
+	// orig are the options as configured, before defaults were applied.
+	orig     SSHOptions
+	opts     SSHOptions
+	mappings pathMappings
+}
+
+func newSSHExecutor(opts SSHOptions, rootDir string) *sshExecutor {
+	orig := opts
+	mappings := pathMappings(opts.Mappings)
+
+	if opts.Workdir == "" {
+		opts.Workdir = mappings.toRemote(rootDir)
+	}
+
+	return &sshExecutor{orig: orig, opts: opts, mappings: mappings}
+}
+
+func (e *sshExecutor) wrap(command []string) []string {
+	args := []string{"ssh", "-T"}
+
+	if e.opts.Port != 0 {
+		args = append(args, "-p", strconv.Itoa(e.opts.Port))
+	}
+
+	args = append(args, e.opts.Args...)
+
+	host := e.opts.Host
+	if e.opts.User != "" {
+		host = e.opts.User + "@" + host
+	}
+
+	remote := make([]string, 0, len(command))
+	for _, arg := range command {
+		remote = append(remote, shellQuote(e.mappings.remapArg(arg)))
+	}
+
+	script := strings.Join(remote, " ")
+	if e.opts.Workdir != "" {
+		script = "cd " + shellQuote(e.opts.Workdir) + " && " + script
+	}
+
+	return append(args, host, script)
+}
+
+func (e *sshExecutor) toLocal(path string) string {
+	return e.mappings.toLocal(path)
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:,+@", r))
+	}) < 0 {
+		return s
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+func (e *sshExecutor) workdir() string {
+	return e.opts.Workdir
+}
+
+func (e *sshExecutor) forDir(dir string) executor {
+	return newSSHExecutor(e.orig, dir)
+}
@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{s: "golangci-lint", want: "golangci-lint"},
+		{s: "./...", want: "./..."},
+		{s: "--out-format=json", want: "--out-format=json"},
+		{s: "a,b:c+d@e", want: "a,b:c+d@e"},
+		{s: "", want: "''"},
+		{s: "two words", want: "'two words'"},
+		{s: "$HOME", want: "'$HOME'"},
+		{s: "it's", want: `'it'"'"'s'`},
+		{s: "a;rm -rf /", want: "'a;rm -rf /'"},
+		{s: "été", want: "'été'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if got := shellQuote(tt.s); got != tt.want {
+				t.Errorf("shellQuote(%q) = %s, want %s", tt.s, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// maxRecentStats is the number of runs golangci-lint/stats returns.
+const maxRecentStats = 20
+
+var (
+	// tookPattern matches the timings golangci-lint logs with -v, as in
+	// "[runner] linters took 3.1s with stages: goanalysis_metalinter: 3s".
+	tookPattern = regexp.MustCompile(`^(?:\[([^\]]+)\] )?(.*?) took ([\dµnmsh.]+)(?: with (?:top \d+ )?stages: (.*))?$`)
+	// memoryPattern matches the memory usage printed with
+	// --print-resources-usage.
+	memoryPattern = regexp.MustCompile(`^Memory: \d+ samples, avg is ([\d.]+)MB, max is ([\d.]+)MB`)
+)
+
+// RunStats are the timings of a run, in seconds, and its memory usage.
+type RunStats struct {
+	Dir      string    `json:"dir"`
+	Time     time.Time `json:"time"`
+	Duration float64   `json:"duration"`
+	// Phases are the loading, linting and processing steps of the run.
+	Phases map[string]float64 `json:"phases,omitempty"`
+	// Linters are the linters and analyzers golangci-lint timed.
+	Linters     map[string]float64 `json:"linters,omitempty"`
+	MemoryAvgMB float64            `json:"memoryAvgMB,omitempty"`
+	MemoryMaxMB float64            `json:"memoryMaxMB,omitempty"`
+}
+
+// LinterStats sums up the time a linter took over the runs of the session.
+type LinterStats struct {
+	Runs  int     `json:"runs"`
+	Total float64 `json:"total"`
+	Max   float64 `json:"max"`
+}
+
+// StatsResult is the response of golangci-lint/stats.
+type StatsResult struct {
+	Runs    []RunStats             `json:"runs"`
+	Linters map[string]LinterStats `json:"linters"`
+}
+
+// statsFlags returns the golangci-lint flags printing the timings and the
+// memory usage of runs.
+func (c *config) statsFlags() []string {
+	if !c.stats || c.backendName != defaultBackend {
+		return nil
+	}
+
+	return []string{"-v", "--print-resources-usage"}
+}
+
+// parseRunStats reads the timings and memory usage logged by golangci-lint
+// in stderr.
+func parseRunStats(stderr []byte) RunStats {
+	stats := RunStats{Phases: make(map[string]float64), Linters: make(map[string]float64)}
+
+	for _, line := range strings.Split(string(stderr), "\n") {
+		if m := logMessagePattern.FindStringSubmatch(line); m != nil {
+			if msg, err := strconv.Unquote(m[1]); err == nil {
+				line = msg
+			}
+		}
+
+		line = strings.TrimSpace(line)
+
+		if m := memoryPattern.FindStringSubmatch(line); m != nil {
+			stats.MemoryAvgMB, _ = strconv.ParseFloat(m[1], 64)
+			stats.MemoryMaxMB, _ = strconv.ParseFloat(m[2], 64)
+
+			continue
+		}
+
+		m := tookPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		d, err := time.ParseDuration(m[3])
+		if err != nil {
+			continue
+		}
+
+		// "Go packages loading at mode 575 (...)" is the loading phase
+		phase := m[2]
+		if i := strings.Index(phase, " at "); i > 0 {
+			phase = phase[:i]
+		}
+
+		if m[1] != "" {
+			phase = m[1] + ": " + phase
+		}
+
+		stats.Phases[phase] = d.Seconds()
+
+		// the stages of the linters and of the analyzers are linters
+		if m[2] != "linters" && !strings.HasSuffix(m[2], "analyzers") {
+			continue
+		}
+
+		for _, stage := range strings.Split(m[4], ", ") {
+			i := strings.LastIndex(stage, ": ")
+			if i < 0 {
+				continue
+			}
+
+			if d, err := time.ParseDuration(stage[i+2:]); err == nil {
+				stats.Linters[stage[:i]] = d.Seconds()
+			}
+		}
+	}
+
+	return stats
+}
+
+// recordStats keeps the stats of a run in dir and logs them at the trace
+// level.
+func (h *langHandler) recordStats(cfg *config, dir string, start time.Time, stderr []byte) {
+	if cfg.statsFlags() == nil {
+		return
+	}
+
+	stats := parseRunStats(stderr)
+	stats.Dir = dir
+	stats.Time = start
+	stats.Duration = time.Since(start).Seconds()
+
+	h.statsMu.Lock()
+	h.recentStats = append(h.recentStats, stats)
+	if len(h.recentStats) > maxRecentStats {
+		h.recentStats = h.recentStats[len(h.recentStats)-maxRecentStats:]
+	}
+
+	for linter, d := range stats.Linters {
+		s := h.linterStats[linter]
+		s.Runs++
+		s.Total += d
+
+		if d > s.Max {
+			s.Max = d
+		}
+
+		h.linterStats[linter] = s
+	}
+	h.statsMu.Unlock()
+
+	linters := make([]string, 0, len(stats.Linters))
+	for linter := range stats.Linters {
+		linters = append(linters, linter)
+	}
+
+	// slowest first
+	sort.Slice(linters, func(i, j int) bool { return stats.Linters[linters[i]] > stats.Linters[linters[j]] })
+
+	fields := logFields{
+		"dir":         dir,
+		"duration":    time.Duration(stats.Duration * float64(time.Second)).String(),
+		"memoryMaxMB": stats.MemoryMaxMB,
+	}
+
+	for _, linter := range linters {
+		fields["linter."+linter] = time.Duration(stats.Linters[linter] * float64(time.Second)).String()
+	}
+
+	h.logger.Event(levelTrace, "golangci-lint-langserver: run stats", fields)
+}
+
+func (h *langHandler) handleStats(_ context.Context, _ *jsonrpc2.Conn, _ *jsonrpc2.Request) (result interface{}, err error) {
+	if !h.config().stats {
+		return nil, errNoStats
+	}
+
+	h.statsMu.Lock()
+	defer h.statsMu.Unlock()
+
+	res := StatsResult{
+		Runs:    append([]RunStats{}, h.recentStats...),
+		Linters: make(map[string]LinterStats, len(h.linterStats)),
+	}
+
+	for linter, s := range h.linterStats {
+		res.Linters[linter] = s
+	}
+
+	return res, nil
+}
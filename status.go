@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// LintStartedParams are sent with golangci-lint/lintStarted when a run
+// starts, for statuslines not supporting workDoneProgress.
+type LintStartedParams struct {
+	Package string        `json:"package"`
+	URIs    []DocumentURI `json:"uris"`
+	Fast    bool          `json:"fast,omitempty"`
+}
+
+// LintFinishedParams are sent with golangci-lint/lintFinished when a run
+// started with golangci-lint/lintStarted ends.
+type LintFinishedParams struct {
+	Package string `json:"package"`
+	// Duration is in milliseconds.
+	Duration int64  `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+func (h *langHandler) notifyStarted(pkg string, uris []DocumentURI, mode lintMode) {
+	h.notify("golangci-lint/lintStarted", &LintStartedParams{Package: pkg, URIs: uris, Fast: mode == modeFast})
+}
+
+func (h *langHandler) notifyFinished(pkg string, start time.Time, err error) {
+	params := &LintFinishedParams{Package: pkg, Duration: time.Since(start).Milliseconds()}
+	if err != nil {
+		params.Error = err.Error()
+	}
+
+	h.notify("golangci-lint/lintFinished", params)
+}
+
+func (h *langHandler) notify(method string, params interface{}) {
+	if err := h.config().conn.Notify(context.Background(), method, params); err != nil {
+		h.logger.Errorf("golangci-lint-langserver: %s", err)
+	}
+}
+
+// QueuedLint is a pending lint in the golangci-lint/queueStatus response.
+type QueuedLint struct {
+	Package     string        `json:"package"`
+	URIs        []DocumentURI `json:"uris"`
+	Interactive bool          `json:"interactive,omitempty"`
+	Fast        bool          `json:"fast,omitempty"`
+	// Waiting is in milliseconds.
+	Waiting int64 `json:"waiting"`
+}
+
+// RunningLint is a running lint in the golangci-lint/queueStatus response.
+type RunningLint struct {
+	Package string        `json:"package"`
+	URIs    []DocumentURI `json:"uris"`
+	Fast    bool          `json:"fast,omitempty"`
+	// Elapsed is in milliseconds.
+	Elapsed int64 `json:"elapsed"`
+}
+
+// QueueStatusResult is the response of golangci-lint/queueStatus. Pending
+// lints are listed in the order they are served, and Debounced lists the
+// documents whose lint waits for changes to pause.
+type QueueStatusResult struct {
+	Debounced []DocumentURI `json:"debounced"`
+	Pending   []QueuedLint  `json:"pending"`
+	Running   []RunningLint `json:"running"`
+}
+
+// runningLint is a lint in progress, reported by golangci-lint/queueStatus.
+type runningLint struct {
+	pkg   string
+	uris  []DocumentURI
+	mode  lintMode
+	start time.Time
+}
+
+// startRunning records the lint of pkg as running until the returned
+// function is called.
+func (h *langHandler) startRunning(pkg string, uris []DocumentURI, mode lintMode) func() {
+	r := &runningLint{pkg: pkg, uris: uris, mode: mode, start: time.Now()}
+
+	h.runningMu.Lock()
+	h.running[r] = struct{}{}
+	h.runningMu.Unlock()
+
+	return func() {
+		h.runningMu.Lock()
+		delete(h.running, r)
+		h.runningMu.Unlock()
+	}
+}
+
+func (h *langHandler) handleQueueStatus(_ context.Context, _ *jsonrpc2.Conn, _ *jsonrpc2.Request) (result interface{}, err error) {
+	now := time.Now()
+	status := QueueStatusResult{
+		Debounced: h.debouncer.pending(),
+		Pending:   []QueuedLint{},
+		Running:   []RunningLint{},
+	}
+
+	pkgs, items := h.queue.snapshot()
+	for i, item := range items {
+		status.Pending = append(status.Pending, QueuedLint{
+			Package:     pkgs[i],
+			URIs:        item.uris,
+			Interactive: item.priority == priorityInteractive,
+			Fast:        item.mode == modeFast,
+			Waiting:     now.Sub(item.queued).Milliseconds(),
+		})
+	}
+
+	h.runningMu.Lock()
+	for r := range h.running {
+		status.Running = append(status.Running, RunningLint{
+			Package: r.pkg,
+			URIs:    r.uris,
+			Fast:    r.mode == modeFast,
+			Elapsed: now.Sub(r.start).Milliseconds(),
+		})
+	}
+	h.runningMu.Unlock()
+
+	// longest running first
+	sort.Slice(status.Running, func(i, j int) bool { return status.Running[i].Elapsed > status.Running[j].Elapsed })
+
+	return status, nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// lintMode selects how thoroughly a package is linted.
+type lintMode int
+
+const (
+	// modeFull is the configured golangci-lint run.
+	modeFull lintMode = iota
+	// modeFast restricts the run to the fast linters.
+	modeFast
+)
+
+const (
+	strategySave    = "save"
+	strategyTwoTier = "twoTier"
+
+	defaultChangeDelay = 500 * time.Millisecond
+	defaultIdleDelay   = 1500 * time.Millisecond
+)
+
+// fastFlag returns the flag restricting golangci-lint to its fast linters,
+// which golangci-lint v2 renamed.
+func (c *config) fastFlag() string {
+	if c.version != "" && compareVersions(c.version, "2.0.0") >= 0 {
+		return "--fast-only"
+	}
+
+	return "--fast"
+}
+
+// debouncer schedules a run once the changes to a file settle: a fast one
+// for the two-tier strategy, a full one for clients that never save.
+type debouncer struct {
+	h      *langHandler
+	mu     sync.Mutex
+	timers map[DocumentURI]*time.Timer
+}
+
+func newDebouncer(h *langHandler) *debouncer {
+	return &debouncer{h: h, timers: make(map[DocumentURI]*time.Timer)}
+}
+
+// pending returns the documents whose run waits for changes to pause.
+func (s *debouncer) pending() []DocumentURI {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	uris := make([]DocumentURI, 0, len(s.timers))
+	for uri := range s.timers {
+		uris = append(uris, uri)
+	}
+
+	sort.Slice(uris, func(i, j int) bool { return uris[i] < uris[j] })
+
+	return uris
+}
+
+// changed schedules a run of uri after delay, postponing the one already
+// scheduled.
+func (s *debouncer) changed(uri DocumentURI, delay time.Duration, mode lintMode) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t, ok := s.timers[uri]; ok {
+		t.Stop()
+	}
+
+	var t *time.Timer
+
+	t = time.AfterFunc(delay, func() {
+		s.mu.Lock()
+		if s.timers[uri] == t {
+			delete(s.timers, uri)
+		}
+		s.mu.Unlock()
+
+		s.h.schedule(uri, priorityInteractive, mode)
+	})
+	s.timers[uri] = t
+}
+
+// cancel drops the run scheduled for uri, if any.
+func (s *debouncer) cancel(uri DocumentURI) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t, ok := s.timers[uri]; ok {
+		t.Stop()
+		delete(s.timers, uri)
+	}
+}
+
+// mergeFast merges the diagnostics of a fast run in dir with those of the
+// latest full run, so that the findings of the slower linters stay visible.
+// Diagnostics of the linters that ran are replaced by the new ones.
+func (h *langHandler) mergeFast(dir string, result *GolangCILintResult, fast map[string][]Diagnostic) map[string][]Diagnostic {
+	ran := make(map[string]bool)
+
+	for _, l := range result.Report.Linters {
+		if l.Enabled {
+			ran[l.Name] = true
+		}
+	}
+
+	if len(ran) == 0 {
+		// older versions do not report the linters; assume only those that
+		// found something ran
+		for _, diagnostics := range fast {
+			for i := range diagnostics {
+				for _, linter := range diagnosticLinters(&diagnostics[i]) {
+					ran[linter] = true
+				}
+			}
+		}
+	}
+
+	h.knownMu.Lock()
+	full := h.known[dir]
+	h.knownMu.Unlock()
+
+	merged := make(map[string][]Diagnostic, len(full))
+
+	for file, diagnostics := range full {
+		for _, d := range diagnostics {
+			if !ranAny(ran, diagnosticLinters(&d)) {
+				merged[file] = append(merged[file], d)
+			}
+		}
+	}
+
+	for file, diagnostics := range fast {
+		merged[file] = append(merged[file], diagnostics...)
+	}
+
+	return merged
+}
+
+// ranAny reports whether one of linters is among those that ran.
+func ranAny(ran map[string]bool, linters []string) bool {
+	for _, linter := range linters {
+		if ran[linter] {
+			return true
+		}
+	}
+
+	return false
+}
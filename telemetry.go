@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+)
+
+const telemetryTimeout = 5 * time.Second
+
+// TelemetryOptions enables recording anonymous lint statistics. Nothing is
+// recorded unless Enabled is set.
+type TelemetryOptions struct {
+	Enabled bool `json:"enabled"`
+	// File is where events are appended as JSON lines, by default in the
+	// user cache directory.
+	File string `json:"file,omitempty"`
+	// Endpoint, if set, receives each event in a POST request.
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// telemetryEvent describes a lint run without anything identifying the
+// user or the project.
+type telemetryEvent struct {
+	Time       time.Time `json:"time"`
+	DurationMS int64     `json:"durationMs"`
+	Outcome    string    `json:"outcome"`
+	Failure    string    `json:"failure,omitempty"`
+	RepoSize   string    `json:"repoSize"`
+	Backend    string    `json:"backend"`
+	Version    string    `json:"version,omitempty"`
+	OS         string    `json:"os"`
+}
+
+// telemetry records run events when enabled.
+type telemetry struct {
+	mu sync.Mutex
+	// sizes caches the size bucket per working directory.
+	sizes map[string]string
+}
+
+func newTelemetry() *telemetry {
+	return &telemetry{sizes: make(map[string]string)}
+}
+
+// recordTelemetry records a run of cfg's command in the background.
+func (h *langHandler) recordTelemetry(cfg *config, d time.Duration, err error, cancelled bool) {
+	opts := cfg.telemetry
+	if opts == nil || !opts.Enabled {
+		return
+	}
+
+	event := telemetryEvent{
+		Time:       time.Now().UTC(),
+		DurationMS: d.Milliseconds(),
+		Outcome:    "ok",
+		Backend:    cfg.backendName,
+		Version:    cfg.version,
+		OS:         runtime.GOOS,
+	}
+
+	var toolErr *toolError
+
+	switch {
+	case cancelled:
+		event.Outcome = "cancelled"
+	case errors.As(err, &toolErr):
+		event.Outcome, event.Failure = "failed", failureClass(toolErr)
+	case err != nil:
+		event.Outcome, event.Failure = "failed", "start"
+	}
+
+	go func() {
+		event.RepoSize = h.telemetry.repoSize(cfg.workingDir())
+
+		if err := writeTelemetry(opts, &event); err != nil {
+			h.logger.Debugf("golangci-lint-langserver: telemetry: %s", err)
+		}
+	}()
+}
+
+// failureClass names the kind of a tool failure by its exit code.
+func failureClass(err *toolError) string {
+	switch err.ExitCode {
+	case 3:
+		return "failure"
+	case 4:
+		return "timeout"
+	case 7:
+		return "error"
+	default:
+		return "exit"
+	}
+}
+
+// repoSize returns the bucket of the number of Go files in dir.
+func (t *telemetry) repoSize(dir string) string {
+	t.mu.Lock()
+	size, ok := t.sizes[dir]
+	t.mu.Unlock()
+
+	if ok {
+		return size
+	}
+
+	n := 0
+
+	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if info.IsDir() && path != dir && (info.Name() == "vendor" || info.Name()[0] == '.') {
+			return filepath.SkipDir
+		}
+
+		if !info.IsDir() && filepath.Ext(path) == ".go" {
+			n++
+		}
+
+		return nil
+	})
+
+	switch {
+	case n < 100:
+		size = "0-99"
+	case n < 1000:
+		size = "100-999"
+	case n < 10000:
+		size = "1000-9999"
+	default:
+		size = "10000+"
+	}
+
+	t.mu.Lock()
+	t.sizes[dir] = size
+	t.mu.Unlock()
+
+	return size
+}
+
+func writeTelemetry(opts *TelemetryOptions, event *telemetryEvent) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	name := opts.File
+	if name == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return err
+		}
+
+		name = filepath.Join(dir, "golangci-lint-langserver", "telemetry.jsonl")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+
+	//nolint:gosec
+	f, err := os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(append(b, '\n'))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil || opts.Endpoint == "" {
+		return err
+	}
+
+	client := http.Client{Timeout: telemetryTimeout}
+
+	resp, err := client.Post(opts.Endpoint, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// transientRetryDelay is how long to wait before retrying a run that failed
+// transiently.
+const transientRetryDelay = time.Second
+
+// exitCodeTimeout is the exit code of golangci-lint when its --timeout
+// expires.
+const exitCodeTimeout = 4
+
+type transientFailure int
+
+const (
+	failureLasting transientFailure = iota
+	// failureCache is golangci-lint reading a corrupted analysis cache.
+	failureCache
+	// failureResources is golangci-lint running out of time or memory.
+	failureResources
+)
+
+var (
+	cacheCorruptionPattern = regexp.MustCompile(`(?i)\bcache\b.*\b(corrupt\w*|malformed|invalid|checksum mismatch|unexpected EOF)|\b(corrupt\w*|malformed|checksum mismatch|unexpected EOF)\b.*\bcache\b`)
+	resourcesPattern       = regexp.MustCompile(`(?i)context deadline exceeded|timeout exceeded|out of memory|signal: killed`)
+)
+
+// classifyFailure tells whether err is a failure that a retry may not hit.
+// Processes killed by a signal while ctx was live are taken as killed for
+// using too much memory.
+func classifyFailure(ctx context.Context, err error) transientFailure {
+	var toolErr *toolError
+	if !errors.As(err, &toolErr) || ctx.Err() != nil {
+		return failureLasting
+	}
+
+	output := toolErr.Report + "\n" + toolErr.Stderr
+
+	switch {
+	case cacheCorruptionPattern.MatchString(output):
+		return failureCache
+	case toolErr.ExitCode == exitCodeTimeout && toolErr.Tool == defaultBackend,
+		toolErr.ExitCode == -1 || toolErr.ExitCode == 137,
+		resourcesPattern.MatchString(output):
+		return failureResources
+	}
+
+	return failureLasting
+}
+
+// retryTransient returns the config to run command with again after it
+// failed with err, if err is transient: the analysis cache of golangci-lint
+// is cleaned first when it is corrupted, and the concurrency is halved when
+// the run took too much time or memory.
+func (h *langHandler) retryTransient(ctx context.Context, cfg *config, command commandFunc, err error) (*config, bool) {
+	failure := classifyFailure(ctx, err)
+	if failure == failureLasting {
+		return nil, false
+	}
+
+	retry := *cfg
+
+	switch failure {
+	case failureCache:
+		if cfg.backendName != defaultBackend || cfg.fallback != "" {
+			return nil, false
+		}
+
+		h.logger.Printf("golangci-lint-langserver: the golangci-lint cache looks corrupted, cleaning it and retrying: %s", err)
+
+		clean := append(append([]string{}, cfg.binaryCommand()...), "cache", "clean")
+		if _, err := h.output(ctx, cfg, clean); err != nil {
+			h.logger.Errorf("golangci-lint-langserver: cleaning the golangci-lint cache: %s", err)
+		}
+	case failureResources:
+		retry.concurrency = reducedConcurrency(cfg.concurrency)
+		if strings.Join(command(&retry), "\x00") == strings.Join(command(cfg), "\x00") {
+			// the command does not take the concurrency
+			return nil, false
+		}
+
+		h.logger.Printf("golangci-lint-langserver: retrying with --concurrency %d: %s", retry.concurrency, err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, false
+	case <-time.After(transientRetryDelay):
+	}
+
+	return &retry, true
+}
+
+// reducedConcurrency halves concurrency, which defaults to the number of
+// CPUs.
+func reducedConcurrency(concurrency int) int {
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+
+	if concurrency /= 2; concurrency < 1 {
+		return 1
+	}
+
+	return concurrency
+}
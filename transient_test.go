@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want transientFailure
+	}{
+		{
+			name: "not a tool error",
+			err:  errors.New("exec: golangci-lint: not found"),
+			want: failureLasting,
+		},
+		{
+			name: "config error",
+			err:  &toolError{Tool: defaultBackend, ExitCode: 3, Stderr: "can't load config"},
+			want: failureLasting,
+		},
+		{
+			name: "corrupted cache",
+			err:  &toolError{Tool: defaultBackend, ExitCode: 3, Stderr: "failed to read from cache: unexpected EOF"},
+			want: failureCache,
+		},
+		{
+			name: "corrupted cache in the report",
+			err:  &toolError{Tool: defaultBackend, ExitCode: 3, Report: "corrupted action cache entry"},
+			want: failureCache,
+		},
+		{
+			name: "wrapped",
+			err:  fmt.Errorf("lint: %w", &toolError{Tool: defaultBackend, ExitCode: 3, Stderr: "cache: checksum mismatch"}),
+			want: failureCache,
+		},
+		{
+			name: "timeout",
+			err:  &toolError{Tool: defaultBackend, ExitCode: exitCodeTimeout},
+			want: failureResources,
+		},
+		{
+			name: "timeout exit code of another tool",
+			err:  &toolError{Tool: "staticcheck", ExitCode: exitCodeTimeout},
+			want: failureLasting,
+		},
+		{
+			name: "killed",
+			err:  &toolError{Tool: defaultBackend, ExitCode: -1},
+			want: failureResources,
+		},
+		{
+			name: "out of memory",
+			err:  &toolError{Tool: "revive", ExitCode: 2, Stderr: "fatal error: runtime: out of memory"},
+			want: failureResources,
+		},
+		{
+			name: "killed after cancellation",
+			ctx:  cancelled,
+			err:  &toolError{Tool: defaultBackend, ExitCode: -1},
+			want: failureLasting,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			if got := classifyFailure(ctx, tt.err); got != tt.want {
+				t.Errorf("classifyFailure(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
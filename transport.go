@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/gorilla/websocket"
+	"github.com/sourcegraph/jsonrpc2"
+	jsonrpc2ws "github.com/sourcegraph/jsonrpc2/websocket"
+)
+
+// listen parses addr of the form tcp:HOST:PORT, unix:PATH, npipe:NAME or
+// ws://HOST:PORT/PATH and listens on it.
+func listen(addr string) (net.Listener, error) {
+	if strings.HasPrefix(addr, "ws://") {
+		u, err := url.Parse(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		return net.Listen("tcp", u.Host)
+	}
+
+	i := strings.Index(addr, ":")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid listen address %q: want tcp:HOST:PORT, unix:PATH or npipe:NAME", addr)
+	}
+
+	network, address := addr[:i], addr[i+1:]
+
+	switch network {
+	case "tcp":
+		return net.Listen(network, address)
+	case "unix":
+		// remove the socket left by a previous server, but never a file
+		// given by mistake
+		if info, err := os.Lstat(address); err == nil {
+			if info.Mode()&os.ModeSocket == 0 {
+				return nil, fmt.Errorf("%s exists and is not a socket", address)
+			}
+
+			if err := os.Remove(address); err != nil {
+				return nil, err
+			}
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		return net.Listen(network, address)
+	case "npipe":
+		return listenPipe(address)
+	}
+
+	return nil, fmt.Errorf("invalid listen address %q: unknown network %q", addr, network)
+}
+
+// serve runs a language server session on rwc until the connection closes.
+func serve(logger logger, shared *sharedState, rwc io.ReadWriteCloser, opts ...jsonrpc2.ConnOpt) {
+	serveStream(logger, shared, jsonrpc2.NewBufferedStream(rwc, jsonrpc2.VSCodeObjectCodec{}), opts...)
+}
+
+func serveStream(logger logger, shared *sharedState, stream jsonrpc2.ObjectStream, opts ...jsonrpc2.ConnOpt) {
+	logger.Printf("golangci-lint-langserver: connections opened")
+
+	h := newLangHandler(logger, shared)
+
+	<-jsonrpc2.NewConn(
+		context.Background(),
+		stream,
+		h.handler(),
+		opts...,
+	).DisconnectNotify()
+
+	// the client went away without shutting the session down
+	h.close()
+
+	logger.Printf("golangci-lint-langserver: connections closed")
+}
+
+// serveListener accepts connections on ln and serves each of them in its own
+// session. The sessions share their caches.
+func serveListener(logger logger, shared *sharedState, ln net.Listener, opts ...jsonrpc2.ConnOpt) error {
+	logger.Printf("golangci-lint-langserver: listening on %s", ln.Addr())
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return err
+		}
+
+		go serve(logger, shared, conn, opts...)
+	}
+}
+
+// serveWebSocket serves a session for every WebSocket connection made to path
+// on ln from the same origin or one of allowedOrigins. The sessions share
+// their caches.
+func serveWebSocket(logger logger, shared *sharedState, ln net.Listener, path string, allowedOrigins []string, opts ...jsonrpc2.ConnOpt) error {
+	logger.Printf("golangci-lint-langserver: listening on ws://%s%s", ln.Addr(), path)
+
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool {
+			if checkOrigin(r, allowedOrigins) {
+				return true
+			}
+
+			logger.Errorf("golangci-lint-langserver: rejecting WebSocket connection from origin %s", r.Header.Get("Origin"))
+
+			return false
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			logger.Errorf("golangci-lint-langserver: %s", err)
+
+			return
+		}
+
+		serveStream(logger, shared, jsonrpc2ws.NewObjectStream(conn), opts...)
+	})
+
+	//nolint:gosec
+	return http.Serve(ln, mux)
+}
+
+// checkOrigin reports whether r may open a session: any web page could
+// otherwise connect and have the server run the command of its choice. Native
+// clients send no Origin header.
+func checkOrigin(r *http.Request, allowedOrigins []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	for _, allowed := range allowedOrigins {
+		if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+
+	u, err := url.Parse(origin)
+
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
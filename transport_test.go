@@ -0,0 +1,67 @@
+package main
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestListenUnix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golangci-lint-langserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// a socket left by a previous server is replaced
+	stale := filepath.Join(dir, "stale.sock")
+
+	ln, err := net.Listen("unix", stale)
+	if err != nil {
+		t.Skip(err)
+	}
+
+	if l, ok := ln.(*net.UnixListener); ok {
+		l.SetUnlinkOnClose(false)
+	}
+
+	ln.Close()
+
+	ln, err = listen("unix:" + stale)
+	if err != nil {
+		t.Fatalf("listen on a stale socket: %v", err)
+	}
+
+	ln.Close()
+
+	// other files are left alone
+	file := filepath.Join(dir, "file")
+	if err := ioutil.WriteFile(file, []byte("data"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if ln, err := listen("unix:" + file); err == nil {
+		ln.Close()
+		t.Fatal("listen on a regular file succeeded")
+	}
+
+	if b, err := ioutil.ReadFile(file); err != nil || string(b) != "data" {
+		t.Errorf("file = %q, %v after listen", b, err)
+	}
+
+	target := filepath.Join(dir, "target")
+	if err := os.Symlink(file, target); err != nil {
+		t.Skip(err)
+	}
+
+	if ln, err := listen("unix:" + target); err == nil {
+		ln.Close()
+		t.Fatal("listen on a symbolic link succeeded")
+	}
+
+	if _, err := os.Lstat(target); err != nil {
+		t.Errorf("symbolic link removed: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	triggerOnOpen       = "onOpen"
+	triggerOnSave       = "onSave"
+	triggerOnChange     = "onChange"
+	triggerOnManualOnly = "onManualOnly"
+
+	commandLint = "golangci-lint.lint"
+)
+
+// runTriggers selects the events running the configured command. Without
+// any, files are only linted with the golangci-lint.lint command.
+type runTriggers struct {
+	open   bool
+	save   bool
+	change bool
+}
+
+// parseRunTriggers parses the runTriggers option. By default files are linted
+// when opened and saved, and also when changed if idle is set.
+func parseRunTriggers(names []string, idle bool) (runTriggers, error) {
+	if len(names) == 0 {
+		return runTriggers{open: true, save: true, change: idle}, nil
+	}
+
+	var t runTriggers
+
+	for _, name := range names {
+		switch name {
+		case triggerOnOpen:
+			t.open = true
+		case triggerOnSave:
+			t.save = true
+		case triggerOnChange:
+			t.change = true
+		case triggerOnManualOnly:
+			if len(names) > 1 {
+				return runTriggers{}, fmt.Errorf("%s cannot be combined with other triggers", name)
+			}
+		default:
+			return runTriggers{}, fmt.Errorf("unknown run trigger %q", name)
+		}
+	}
+
+	return t, nil
+}
+
+func (t runTriggers) manualOnly() bool {
+	return !t.open && !t.save && !t.change
+}
+
+// handleLintCommand lints the files given as arguments, or every open file.
+func (h *langHandler) handleLintCommand(params *ExecuteCommandParams) (result interface{}, err error) {
+	uris := make([]DocumentURI, 0, len(params.Arguments))
+
+	for _, arg := range params.Arguments {
+		var uri DocumentURI
+		if err := json.Unmarshal(arg, &uri); err != nil {
+			return nil, err
+		}
+
+		uris = append(uris, uri)
+	}
+
+	if len(uris) == 0 {
+		uris = h.openDocuments()
+	}
+
+	for _, uri := range uris {
+		h.debouncer.cancel(uri)
+		h.enqueue(uri, priorityInteractive)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	trustAction    = "Trust"
+	distrustAction = "Don't trust"
+)
+
+// commandOptions are the options choosing what the server executes, reads or
+// writes outside of the workspace, which a configuration file in the
+// workspace only sets once the user trusts it: a cloned repository must not
+// run arbitrary commands.
+var commandOptions = []string{
+	"backend", "command", "commandResolution", "container", "env", "folders", "install",
+	"packagesDriver", "reportFile", "sandbox", "ssh", "telemetry",
+}
+
+// untrustedConfig is a configuration file of the workspace whose command
+// options were ignored.
+type untrustedConfig struct {
+	name string
+	// sum is the hash of the content the user is asked to trust.
+	sum  string
+	keys []string
+}
+
+// trustFile returns the file recording the trusted workspace configuration
+// files.
+func trustFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "golangci-lint-langserver", "trusted.json"), nil
+}
+
+// loadTrusted returns the hashes of the trusted configuration files by name.
+func loadTrusted() (map[string]string, error) {
+	trusted := make(map[string]string)
+
+	name, err := trustFile()
+	if err != nil {
+		return trusted, err
+	}
+
+	b, err := ioutil.ReadFile(name)
+	if os.IsNotExist(err) {
+		return trusted, nil
+	}
+
+	if err != nil {
+		return trusted, err
+	}
+
+	return trusted, json.Unmarshal(b, &trusted)
+}
+
+// saveTrusted records that the content of name hashing to sum is trusted.
+func saveTrusted(name, sum string) error {
+	trusted, err := loadTrusted()
+	if err != nil {
+		return err
+	}
+
+	trusted[name] = sum
+
+	b, err := json.MarshalIndent(trusted, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	file, err := trustFile()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return err
+	}
+
+	tmp := file + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, file)
+}
+
+// checkTrust returns the command options that the configuration file name
+// sets without being trusted, nil if there are none.
+func (h *langHandler) checkTrust(name string) *untrustedConfig {
+	if h.trustWorkspace {
+		return nil
+	}
+
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil
+	}
+
+	var v map[string]interface{}
+	if err := yaml.Unmarshal(b, &v); err != nil {
+		return nil
+	}
+
+	var keys []string
+
+	for _, key := range commandOptions {
+		if _, ok := v[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	hash := sha256.Sum256(b)
+	sum := hex.EncodeToString(hash[:])
+
+	trusted, err := loadTrusted()
+	if err != nil {
+		h.logger.Errorf("golangci-lint-langserver: loading trusted configurations: %s", err)
+	}
+
+	h.trustMu.Lock()
+	answered := h.trustAnswers[sum]
+	h.trustMu.Unlock()
+
+	if answered || trusted[name] == sum {
+		return nil
+	}
+
+	sort.Strings(keys)
+
+	return &untrustedConfig{name: name, sum: sum, keys: keys}
+}
+
+// confirmTrust asks the user whether to trust the command options of the
+// workspace configuration file that cfg ignored, once per content, and loads
+// the configuration again if so.
+func (h *langHandler) confirmTrust(cfg *config) {
+	u := cfg.untrusted
+	if u == nil || cfg.conn == nil {
+		return
+	}
+
+	h.trustMu.Lock()
+	_, asked := h.trustAnswers[u.sum]
+	if !asked {
+		h.trustAnswers[u.sum] = false
+	}
+	h.trustMu.Unlock()
+
+	if asked {
+		return
+	}
+
+	params := &ShowMessageRequestParams{
+		Type: MTWarning,
+		Message: fmt.Sprintf("golangci-lint-langserver: %s sets %s, choosing the commands run while linting. Trust this configuration?",
+			u.name, strings.Join(u.keys, ", ")),
+		Actions: []MessageActionItem{{Title: trustAction}, {Title: distrustAction}},
+	}
+
+	var item *MessageActionItem
+	if err := cfg.conn.Call(context.Background(), "window/showMessageRequest", params, &item); err != nil {
+		h.logger.Errorf("golangci-lint-langserver: asking to trust %s: %s", u.name, err)
+
+		return
+	}
+
+	if item == nil || item.Title != trustAction {
+		return
+	}
+
+	h.trustMu.Lock()
+	h.trustAnswers[u.sum] = true
+	h.trustMu.Unlock()
+
+	if err := saveTrusted(u.name, u.sum); err != nil {
+		h.logger.Errorf("golangci-lint-langserver: recording trusted configuration: %s", err)
+	}
+
+	h.reload("workspace configuration trusted")
+}
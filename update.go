@@ -0,0 +1,234 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+const (
+	serverModule     = "github.com/nametake/golangci-lint-langserver"
+	latestReleaseURL = "https://api.github.com/repos/nametake/golangci-lint-langserver/releases/latest"
+)
+
+// pseudoVersionPattern matches the timestamp and commit hash ending a
+// pseudo-version, before any +dirty.
+var pseudoVersionPattern = regexp.MustCompile(`[-.]\d{14}-[0-9a-f]{12}(\+dirty)?$`)
+
+type release struct {
+	TagName string `json:"tag_name"`
+	Assets  []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// serverVersion returns the module version the server was built from, which
+// is only known for binaries built with go install of a release. Builds of a
+// checkout get a pseudo-version, or (devel) before Go 1.24.
+func serverVersion() string {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok || bi.Main.Version == "(devel)" || isPseudoVersion(bi.Main.Version) {
+		return ""
+	}
+
+	return bi.Main.Version
+}
+
+// isPseudoVersion reports whether v is a pseudo-version, as in
+// v0.0.0-20261014183115-74521c73da50+dirty.
+func isPseudoVersion(v string) bool {
+	return pseudoVersionPattern.MatchString(v)
+}
+
+func latestRelease() (*release, error) {
+	//nolint:noctx
+	resp, err := http.Get(latestReleaseURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", latestReleaseURL, resp.Status)
+	}
+
+	var r release
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// archiveURL returns the URL of the release archive for the current platform.
+func (r *release) archiveURL() (string, bool) {
+	platform := runtime.GOOS + "_" + runtime.GOARCH
+
+	for _, a := range r.Assets {
+		if strings.Contains(a.Name, platform) && (strings.HasSuffix(a.Name, ".tar.gz") || strings.HasSuffix(a.Name, ".zip")) {
+			return a.URL, true
+		}
+	}
+
+	return "", false
+}
+
+// checksumsURL returns the URL of the checksums of the release archives.
+func (r *release) checksumsURL() (string, bool) {
+	for _, a := range r.Assets {
+		if strings.HasSuffix(a.Name, "checksums.txt") {
+			return a.URL, true
+		}
+	}
+
+	return "", false
+}
+
+// selfUpdate replaces the running binary with the latest release and writes
+// what it did to w. It returns the exit code of the update subcommand.
+func selfUpdate(w io.Writer, args []string) int {
+	fs := flag.NewFlagSet("update", flag.ContinueOnError)
+	fs.SetOutput(w)
+
+	force := fs.Bool("force", false, "replace a binary of unknown version, such as a development build")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	current := serverVersion()
+	if current == "" && !*force {
+		fmt.Fprintln(w, "the version of golangci-lint-langserver is unknown, as for development builds; run update -force to replace it anyway")
+
+		return 1
+	}
+
+	r, err := latestRelease()
+	if err != nil {
+		fmt.Fprintf(w, "checking the latest release: %s\n", err)
+
+		return 1
+	}
+
+	if current != "" && compareVersions(current, r.TagName) >= 0 {
+		fmt.Fprintf(w, "golangci-lint-langserver %s is up to date\n", current)
+
+		return 0
+	}
+
+	url, ok := r.archiveURL()
+	if !ok {
+		fmt.Fprintf(w, "no %s release archive for %s/%s; run go install %s@%s\n", r.TagName, runtime.GOOS, runtime.GOARCH, serverModule, r.TagName)
+
+		return 1
+	}
+
+	checksums, ok := r.checksumsURL()
+	if !ok {
+		fmt.Fprintf(w, "no checksums published for %s, not updating\n", r.TagName)
+
+		return 1
+	}
+
+	if err := replaceExecutable(url, checksums); err != nil {
+		fmt.Fprintf(w, "updating to %s: %s\n", r.TagName, err)
+
+		return 1
+	}
+
+	fmt.Fprintf(w, "golangci-lint-langserver updated to %s\n", r.TagName)
+
+	return 0
+}
+
+// replaceExecutable replaces the running binary with the one of the archive
+// at url, once checked against the checksums at checksumsURL.
+func replaceExecutable(url, checksumsURL string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return err
+	}
+
+	body, err := fetch(url)
+	if err != nil {
+		return err
+	}
+
+	checksums, err := fetch(checksumsURL)
+	if err != nil {
+		return err
+	}
+
+	if err := verifyChecksum(checksums, path.Base(url), body); err != nil {
+		return err
+	}
+
+	name := "golangci-lint-langserver"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+
+	var r io.Reader
+	if strings.HasSuffix(url, ".zip") {
+		r, err = extractZip(body, name)
+	} else {
+		r, err = extractTarGz(body, name)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if runtime.GOOS == "windows" {
+		// a running executable cannot be replaced, but it can be renamed
+		old := exe + ".old"
+		_ = os.Remove(old)
+
+		if err := os.Rename(exe, old); err != nil {
+			return err
+		}
+	}
+
+	return writeExecutable(exe, r)
+}
+
+// updateChecker looks for a newer release once per process.
+type updateChecker struct {
+	once   sync.Once
+	latest string
+}
+
+// newer returns the tag of the latest release if it is newer than the server.
+func (u *updateChecker) newer() (string, bool) {
+	u.once.Do(func() {
+		if r, err := latestRelease(); err == nil {
+			u.latest = r.TagName
+		}
+	})
+
+	current := serverVersion()
+
+	return u.latest, u.latest != "" && current != "" && compareVersions(current, u.latest) < 0
+}
+
+// notifyUpdate tells the user about a newer release.
+func (h *langHandler) notifyUpdate() {
+	if latest, ok := h.updates.newer(); ok {
+		h.showMessage(MTInfo, fmt.Sprintf("golangci-lint-langserver %s is available, run golangci-lint-langserver update to install it", latest))
+	}
+}
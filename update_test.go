@@ -0,0 +1,23 @@
+package main
+
+import "testing"
+
+func TestIsPseudoVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"v0.0.6", false},
+		{"v1.2.3-rc.1", false},
+		{"v0.0.0-20261014183115-74521c73da50", true},
+		{"v0.0.0-20261014183115-74521c73da50+dirty", true},
+		{"v1.2.4-0.20261014183115-74521c73da50", true},
+		{"v1.2.3-pre.0.20261014183115-74521c73da50", true},
+	}
+
+	for _, tt := range tests {
+		if got := isPseudoVersion(tt.version); got != tt.want {
+			t.Errorf("isPseudoVersion(%q) = %v, want %v", tt.version, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var driveLetterPattern = regexp.MustCompile(`^/[A-Za-z]:`)
+
+// uriToPath converts a file URI to a filesystem path. Percent-encoded
+// characters are decoded, and Windows drive letters and UNC hosts are
+// converted to their native forms.
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return strings.TrimPrefix(uri, "file://")
+	}
+
+	p := u.Path
+
+	switch {
+	case u.Host != "" && u.Host != "localhost":
+		// UNC path: file://server/share/file.go
+		return filepath.FromSlash("//" + u.Host + p)
+	case driveLetterPattern.MatchString(p):
+		// file:///C:/file.go
+		p = strings.ToUpper(p[1:2]) + p[2:]
+	}
+
+	return filepath.FromSlash(p)
+}
+
+// pathToURI converts an absolute filesystem path to a file URI.
+func pathToURI(path string) DocumentURI {
+	p := filepath.ToSlash(path)
+
+	u := url.URL{Scheme: "file"}
+
+	switch {
+	case strings.HasPrefix(p, "//"):
+		// UNC path: //server/share/file.go
+		rest := p[2:]
+		if i := strings.Index(rest, "/"); i >= 0 {
+			u.Host, u.Path = rest[:i], rest[i:]
+		} else {
+			u.Host, u.Path = rest, "/"
+		}
+	case len(p) >= 2 && p[1] == ':':
+		u.Path = "/" + strings.ToUpper(p[:1]) + p[1:]
+	default:
+		u.Path = p
+	}
+
+	return DocumentURI(u.String())
+}
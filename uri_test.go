@@ -0,0 +1,73 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestURIToPath(t *testing.T) {
+	tests := []struct {
+		name string
+		uri  string
+		want string
+	}{
+		{name: "unix", uri: "file:///home/user/main.go", want: "/home/user/main.go"},
+		{name: "percent encoded", uri: "file:///home/user/my%20project/main.go", want: "/home/user/my project/main.go"},
+		{name: "encoded unicode", uri: "file:///tmp/%C3%A9t%C3%A9/main.go", want: "/tmp/été/main.go"},
+		{name: "localhost", uri: "file://localhost/tmp/main.go", want: "/tmp/main.go"},
+		{name: "drive letter", uri: "file:///c:/src/main.go", want: "C:/src/main.go"},
+		{name: "encoded drive letter", uri: "file:///c%3A/src/main.go", want: "C:/src/main.go"},
+		{name: "unc", uri: "file://server/share/main.go", want: "//server/share/main.go"},
+		{name: "not a uri", uri: "/tmp/main.go", want: "/tmp/main.go"},
+		{name: "other scheme", uri: "untitled:Untitled-1", want: "untitled:Untitled-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := uriToPath(tt.uri); got != filepath.FromSlash(tt.want) {
+				t.Errorf("uriToPath(%q) = %q, want %q", tt.uri, got, filepath.FromSlash(tt.want))
+			}
+		})
+	}
+}
+
+func TestPathToURI(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want DocumentURI
+	}{
+		{name: "unix", path: "/home/user/main.go", want: "file:///home/user/main.go"},
+		{name: "space", path: "/home/user/my project/main.go", want: "file:///home/user/my%20project/main.go"},
+		{name: "unicode", path: "/tmp/été/main.go", want: "file:///tmp/%C3%A9t%C3%A9/main.go"},
+		{name: "hash", path: "/tmp/a#b/main.go", want: "file:///tmp/a%23b/main.go"},
+		{name: "drive letter", path: "c:/src/main.go", want: "file:///C:/src/main.go"},
+		{name: "unc", path: "//server/share/main.go", want: "file://server/share/main.go"},
+		{name: "unc share", path: "//server", want: "file://server/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pathToURI(filepath.FromSlash(tt.path)); got != tt.want {
+				t.Errorf("pathToURI(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestURIRoundTrip(t *testing.T) {
+	for _, path := range []string{
+		"/home/user/main.go",
+		"/home/user/my project/main.go",
+		"/tmp/100%/main.go",
+		"/tmp/a?b/main.go",
+		"C:/src/main.go",
+		"//server/share/main.go",
+	} {
+		path = filepath.FromSlash(path)
+
+		if got := uriToPath(string(pathToURI(path))); got != path {
+			t.Errorf("uriToPath(pathToURI(%q)) = %q", path, got)
+		}
+	}
+}
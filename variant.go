@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go/build"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// VariantOptions describe a build configuration the workspace is linted in,
+// such as another GOOS.
+type VariantOptions struct {
+	Name   string   `json:"name,omitempty"`
+	GOOS   string   `json:"goos,omitempty"`
+	GOARCH string   `json:"goarch,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+}
+
+// unusedLinters report code that no build of the package uses, which code of
+// another variant may use.
+var unusedLinters = map[string]bool{
+	"deadcode":    true,
+	"structcheck": true,
+	"unparam":     true,
+	"unused":      true,
+	"varcheck":    true,
+}
+
+// label names v in the diagnostics found in some variants only.
+func (v *VariantOptions) label() string {
+	if v.Name != "" {
+		return v.Name
+	}
+
+	var parts []string
+
+	if v.GOOS != "" || v.GOARCH != "" {
+		parts = append(parts, strings.Trim(v.GOOS+"/"+v.GOARCH, "/"))
+	}
+
+	return strings.Join(append(parts, v.Tags...), ",")
+}
+
+// compiles reports whether the build of v includes the Go file at path.
+func (v *VariantOptions) compiles(path string) bool {
+	ctx := build.Default
+	ctx.BuildTags = v.Tags
+
+	if v.GOOS != "" {
+		ctx.GOOS = v.GOOS
+	}
+
+	if v.GOARCH != "" {
+		ctx.GOARCH = v.GOARCH
+	}
+
+	ok, err := ctx.MatchFile(filepath.Dir(path), filepath.Base(path))
+
+	return err != nil || ok
+}
+
+// forVariant returns the config of the runs in v.
+func (c *config) forVariant(v *VariantOptions) *config {
+	vc := *c
+	vc.variant = v
+
+	vc.env = make(map[string]string, len(c.env)+2)
+	for k, value := range c.env {
+		vc.env[k] = value
+	}
+
+	if v.GOOS != "" {
+		vc.env["GOOS"] = v.GOOS
+	}
+
+	if v.GOARCH != "" {
+		vc.env["GOARCH"] = v.GOARCH
+	}
+
+	return &vc
+}
+
+// variantFlags returns the flags of golangci-lint run for the build tags of
+// the variant being linted.
+func (c *config) variantFlags() []string {
+	if c.variant == nil || len(c.variant.Tags) == 0 {
+		return nil
+	}
+
+	return []string{"--build-tags", strings.Join(c.variant.Tags, ",")}
+}
+
+// runVariants runs command once per variant and merges the issues. Issues
+// reported by every variant compiling their file are kept as they are; the
+// others are labelled with the variants reporting them, unless they are
+// about unused code, which the other variants use.
+func (h *langHandler) runVariants(ctx context.Context, cfg *config, command commandFunc) (*GolangCILintResult, error) {
+	type reported struct {
+		issue    Issue
+		variants []string
+	}
+
+	var order []string
+
+	issues := make(map[string]*reported)
+
+	for i := range cfg.variants {
+		v := &cfg.variants[i]
+
+		r, err := h.timedRun(ctx, cfg.forVariant(v), command)
+		if err != nil {
+			return nil, fmt.Errorf("variant %s: %w", v.label(), err)
+		}
+
+		for _, issue := range r.Issues {
+			key := strings.Join([]string{
+				issue.FromLinter,
+				issue.Pos.Filename,
+				strconv.Itoa(issue.Pos.Line),
+				strconv.Itoa(issue.Pos.Column),
+				issue.Text,
+			}, "\x00")
+
+			rep, ok := issues[key]
+			if !ok {
+				rep = &reported{issue: issue}
+				issues[key] = rep
+				order = append(order, key)
+			}
+
+			rep.variants = append(rep.variants, v.label())
+		}
+	}
+
+	var result GolangCILintResult
+
+	for _, key := range order {
+		rep := issues[key]
+		path := cfg.issuePath(rep.issue.Pos.Filename)
+
+		compiling := 0
+
+		for i := range cfg.variants {
+			if cfg.variants[i].compiles(path) {
+				compiling++
+			}
+		}
+
+		if len(rep.variants) < compiling {
+			if unusedLinters[rep.issue.FromLinter] {
+				continue
+			}
+
+			rep.issue.Variants = rep.variants
+		}
+
+		result.Issues = append(result.Issues, rep.issue)
+	}
+
+	return &result, nil
+}
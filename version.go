@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	versionPattern   = regexp.MustCompile(`version v?(\d+\.\d+\.\d+)`)
+	goVersionPattern = regexp.MustCompile(`built with go(\d+\.\d+(?:\.\d+)?)`)
+)
+
+type versionInfo struct {
+	Version   string
+	GoVersion string
+}
+
+// detectVersion returns the version reported by `<command> --version`.
+func detectVersion(command ...string) (versionInfo, error) {
+	args := append(append([]string{}, command[1:]...), "--version")
+
+	//nolint:gosec
+	b, err := exec.Command(command[0], args...).CombinedOutput()
+	if err != nil {
+		return versionInfo{}, err
+	}
+
+	return parseVersion(b)
+}
+
+// golangciLintVersion returns the version of the golangci-lint of c, run in
+// its container, on its remote host or in its sandbox and environment as the
+// lints are.
+func (c *config) golangciLintVersion() (versionInfo, error) {
+	command := append(append([]string{}, c.binaryCommand()...), "--version")
+
+	command, err := c.sandboxed(c.executorCommand(command))
+	if err != nil {
+		return versionInfo{}, err
+	}
+
+	//nolint:gosec
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Dir = c.workingDir()
+	cmd.Env = c.commandEnv()
+
+	b, err := cmd.CombinedOutput()
+	if err != nil {
+		return versionInfo{}, err
+	}
+
+	return parseVersion(b)
+}
+
+// parseVersion parses the output of golangci-lint --version.
+func parseVersion(b []byte) (versionInfo, error) {
+	m := versionPattern.FindSubmatch(b)
+	if m == nil {
+		return versionInfo{}, errUnknownVersion
+	}
+
+	info := versionInfo{Version: string(m[1])}
+
+	if m := goVersionPattern.FindSubmatch(b); m != nil {
+		info.GoVersion = string(m[1])
+	}
+
+	return info, nil
+}
+
+// compareVersions compares two dotted versions, ignoring a leading "v".
+func compareVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+
+	return 0
+}
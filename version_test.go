@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.55.2", b: "1.55.2", want: 0},
+		{a: "v1.55.2", b: "1.55.2", want: 0},
+		{a: "1.55.2", b: "1.56.0", want: -1},
+		{a: "1.56.0", b: "1.55.2", want: 1},
+		{a: "1.9.0", b: "1.10.0", want: -1},
+		{a: "2.0", b: "1.99.99", want: 1},
+		{a: "1.55", b: "1.55.0", want: 0},
+		{a: "1.55", b: "1.55.1", want: -1},
+		{a: "", b: "0.0.1", want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			if got := compareVersions(tt.a, tt.b); got != tt.want {
+				t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+const vetBackend = "govet"
+
+// goVetBackend runs go vet -json, which prints a JSON object per package on
+// stderr or, depending on the Go version, stdout.
+type goVetBackend struct{}
+
+// vetDiagnostic is a finding of an analyzer in the output of go vet -json.
+type vetDiagnostic struct {
+	Posn    string `json:"posn"`
+	Message string `json:"message"`
+}
+
+func (goVetBackend) defaultCommand() []string {
+	return []string{"go", "vet", "-json", "./..."}
+}
+
+func (goVetBackend) combinedOutput() {}
+
+func (goVetBackend) decode(r io.Reader, result *GolangCILintResult) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+
+	var block strings.Builder
+
+	// the objects start and end with a brace alone on a line, and are mixed
+	// with comments and build errors
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case line == "{":
+			block.Reset()
+			block.WriteString(line)
+		case line == "}" && block.Len() > 0:
+			block.WriteString(line)
+
+			var pkgs map[string]map[string][]vetDiagnostic
+			if err := json.Unmarshal([]byte(block.String()), &pkgs); err != nil {
+				return err
+			}
+
+			block.Reset()
+
+			for _, analyzers := range pkgs {
+				for analyzer, diagnostics := range analyzers {
+					for _, d := range diagnostics {
+						result.Issues = append(result.Issues, vetIssue(analyzer, d))
+					}
+				}
+			}
+		case block.Len() > 0:
+			block.WriteString(line)
+		}
+	}
+
+	return scanner.Err()
+}
+
+func vetIssue(analyzer string, d vetDiagnostic) Issue {
+	var issue Issue
+	issue.FromLinter = "govet"
+	// golangci-lint reports govet findings the same way
+	issue.Text = analyzer + ": " + d.Message
+	issue.Pos.Filename = d.Posn
+
+	// posn is file:line:col, where file may contain colons
+	if i := strings.LastIndex(d.Posn, ":"); i >= 0 {
+		if j := strings.LastIndex(d.Posn[:i], ":"); j >= 0 {
+			line, err1 := strconv.Atoi(d.Posn[j+1 : i])
+			col, err2 := strconv.Atoi(d.Posn[i+1:])
+
+			if err1 == nil && err2 == nil {
+				issue.Pos.Filename = d.Posn[:j]
+				issue.Pos.Line = line
+				issue.Pos.Column = col
+			}
+		}
+	}
+
+	return issue
+}
+
+func (goVetBackend) classify(_ *config, code int) runKind {
+	if code == exitCodeSuccess || code == exitCodeIssuesFound {
+		return runIssues
+	}
+
+	return runFailed
+}
+
+// combinedBackend is implemented by backends printing their results on
+// stdout or stderr.
+type combinedBackend interface {
+	combinedOutput()
+}
+
+// fallBackToVet switches to go vet when the golangci-lint binary cannot be
+// found, so that basic diagnostics are still published.
+func (c *config) fallBackToVet() {
+	if c.backendName != defaultBackend || c.executor != nil || c.install != nil || c.report != nil || c.fixtures != nil ||
+		len(c.command) == 0 {
+		return
+	}
+
+	if _, err := exec.LookPath(c.command[0]); err == nil {
+		return
+	}
+
+	c.fallback = c.command[0]
+	c.backendName = vetBackend
+	c.backend = goVetBackend{}
+	c.command = goVetBackend{}.defaultCommand()
+}
@@ -0,0 +1,57 @@
+package main
+
+import "testing"
+
+func TestVetIssue(t *testing.T) {
+	tests := []struct {
+		name     string
+		analyzer string
+		d        vetDiagnostic
+		file     string
+		line     int
+		column   int
+	}{
+		{
+			name:     "position",
+			analyzer: "printf",
+			d:        vetDiagnostic{Posn: "/src/main.go:12:3", Message: "wrong type"},
+			file:     "/src/main.go",
+			line:     12,
+			column:   3,
+		},
+		{
+			name:     "colons in the path",
+			analyzer: "unusedresult",
+			d:        vetDiagnostic{Posn: `C:\src\a:b.go:4:1`, Message: "result unused"},
+			file:     `C:\src\a:b.go`,
+			line:     4,
+			column:   1,
+		},
+		{
+			name:     "no position",
+			analyzer: "printf",
+			d:        vetDiagnostic{Posn: "main.go", Message: "wrong type"},
+			file:     "main.go",
+		},
+		{
+			name:     "invalid position",
+			analyzer: "printf",
+			d:        vetDiagnostic{Posn: "main.go:x:3", Message: "wrong type"},
+			file:     "main.go:x:3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := vetIssue(tt.analyzer, tt.d)
+
+			if issue.FromLinter != "govet" || issue.Text != tt.analyzer+": "+tt.d.Message {
+				t.Errorf("issue from %s: %q", issue.FromLinter, issue.Text)
+			}
+
+			if issue.Pos.Filename != tt.file || issue.Pos.Line != tt.line || issue.Pos.Column != tt.column {
+				t.Errorf("position = %s:%d:%d, want %s:%d:%d", issue.Pos.Filename, issue.Pos.Line, issue.Pos.Column, tt.file, tt.line, tt.column)
+			}
+		})
+	}
+}
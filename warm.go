@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// warmUp runs golangci-lint once over the whole workspace with a low
+// priority to populate its analysis cache. The result is discarded.
+func (h *langHandler) warmUp() {
+	cfg := h.config()
+	command := cfg.niceLintCommand()
+
+	done := h.startRunning("./...", []DocumentURI{}, modeFull)
+	defer done()
+
+	h.runMu.Lock()
+	defer h.runMu.Unlock()
+
+	start := time.Now()
+
+	if _, err := h.run(h.ctx, cfg, command); err != nil {
+		h.logger.Errorf("golangci-lint-langserver: warming cache: %s", err)
+
+		return
+	}
+
+	h.logger.Printf("golangci-lint-langserver: cache warmed in %s", time.Since(start))
+}
+
+// niceCommand lowers the priority of command where nice is available.
+func niceCommand(command []string) []string {
+	if runtime.GOOS != "windows" {
+		if nice, err := exec.LookPath("nice"); err == nil {
+			return append([]string{nice, "-n", "19"}, command...)
+		}
+	}
+
+	return command
+}
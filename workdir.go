@@ -0,0 +1,93 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// where the working directory of the runs comes from, in the order they are
+// tried for a file
+const (
+	dirFromSetting = "workingDir setting"
+	dirFromModule  = "nearest go.mod"
+	dirFromFolder  = "workspace folder"
+	dirFromRoot    = "workspace root"
+	dirFromFile    = "file directory"
+	dirFromProcess = "process directory"
+)
+
+// forModule adapts c to lint the file at path in its nearest module, as
+// golangci-lint does not lint nested modules from their parent, unless the
+// working directory is configured. Workspaces opened below their module root
+// keep running there.
+func (c *config) forModule(path string) *config {
+	if c.explicitDir != "" {
+		return c
+	}
+
+	modDir, ok := findModule(filepath.Dir(path))
+	if !ok {
+		return c
+	}
+
+	root, mod := normalizePath(c.rootDir), normalizePath(modDir)
+	if c.rootDir != "" && (mod == root || strings.HasPrefix(root, mod+string(filepath.Separator))) {
+		return c
+	}
+
+	return c.withDir(modDir, dirFromModule)
+}
+
+// withDir returns a copy of c running in dir, found from source.
+func (c *config) withDir(dir, source string) *config {
+	fc := *c
+	fc.sessionRoot = c.workspaceRoot()
+	fc.rootDir = dir
+	fc.rootURI = string(pathToURI(dir))
+	fc.dirSource = source
+
+	if c.executor != nil {
+		fc.executor = c.executor.forDir(dir)
+	}
+
+	return &fc
+}
+
+// workspaceRoot returns the root of the session of c, which the configs of
+// files running in their module or folder keep.
+func (c *config) workspaceRoot() string {
+	if c.sessionRoot != "" {
+		return c.sessionRoot
+	}
+
+	return c.rootDir
+}
+
+// workingDirSource tells where the working directory of c comes from.
+func (c *config) workingDirSource() string {
+	switch {
+	case c.explicitDir != "":
+		return dirFromSetting
+	case c.rootDir == "":
+		return dirFromProcess
+	}
+
+	if c.dirSource == "" {
+		return dirFromRoot
+	}
+
+	return c.dirSource
+}
+
+// resolveDir resolves the workingDir setting against the workspace root.
+func resolveDir(rootDir, dir string) string {
+	if dir == "" {
+		return ""
+	}
+
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(rootDir, dir)
+	}
+
+	return filepath.Clean(dir)
+}
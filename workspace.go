@@ -0,0 +1,87 @@
+package main
+
+import "time"
+
+// lintWorkspace runs the command once over the whole workspace with a low
+// priority and publishes the diagnostics of every file, so that they are
+// listed before any file is opened.
+func (h *langHandler) lintWorkspace() {
+	cfg := h.config()
+
+	done := h.startRunning("./...", []DocumentURI{}, modeFull)
+	defer done()
+
+	start := time.Now()
+
+	pending, err := h.workspaceDiagnostics(cfg, niceLintArgs())
+	if err != nil {
+		if h.ctx.Err() == nil {
+			h.reportFailure(err)
+			h.publishConfigError(cfg, err)
+		}
+
+		return
+	}
+
+	h.publishConfigError(cfg, nil)
+	h.publishAll(pending)
+
+	h.logger.Printf("golangci-lint-langserver: workspace linted in %s", time.Since(start))
+}
+
+// workspaceDiagnostics runs command over the whole workspace and returns the
+// diagnostics of the files having issues, in the order golangci-lint reported
+// them.
+func (h *langHandler) workspaceDiagnostics(cfg *config, command commandFunc) ([]pendingPublish, error) {
+	var (
+		result *GolangCILintResult
+		err    error
+	)
+
+	h.runMu.Lock()
+	if cfg.report != nil {
+		result, err = cfg.report.load()
+	} else {
+		result, err = h.timedRun(h.ctx, cfg, command)
+		if _, ok := configErrorMessage(err); err != nil && !ok && h.ctx.Err() == nil {
+			result, err = h.lintGroups(cfg, err)
+		}
+	}
+	h.runMu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	files := cfg.diagnosticsByFile(result)
+	h.remember(cfg.workingDir(), files, cfg.maxTrackedFiles)
+
+	changes := h.gitChanges(cfg)
+	published := make(map[string]bool, len(files))
+	pending := make([]pendingPublish, 0, len(files))
+
+	for _, issue := range result.Issues {
+		filename := cfg.issuePath(issue.Pos.Filename)
+		key := normalizePath(filename)
+
+		if published[key] {
+			continue
+		}
+
+		published[key] = true
+		uri := pathToURI(filename)
+
+		diagnostics, ok := files[key]
+		if !ok || h.excluded(cfg.forFile(filename), uri) || changes != nil && !changes.dirty(filename) {
+			continue
+		}
+
+		if changes != nil && cfg.gitMode == gitChangedLines {
+			diagnostics = changes.filterChangedLines(filename, diagnostics)
+		}
+
+		pending = append(pending, pendingPublish{uri: uri, diagnostics: diagnostics})
+	}
+
+	return pending, nil
+}